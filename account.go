@@ -0,0 +1,179 @@
+package segwit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	bip39 "github.com/tyler-smith/go-bip39"
+)
+
+const (
+	// DefaultGapLimit is the number of consecutive unused addresses Scan
+	// tolerates before stopping, per BIP44.
+	DefaultGapLimit = 20
+
+	// BranchExternal and BranchInternal name the two chains BIP44 derives
+	// under each account, for receive and change addresses respectively.
+	BranchExternal uint32 = 0
+	BranchInternal uint32 = 1
+
+	ErrAccountExists = "account already exists"
+)
+
+// AccountManager holds a wallet's master key once and hands out BIP44/BIP84
+// accounts at m/84'/coin'/account' on demand.
+type AccountManager struct {
+	masterKey *hdkeychain.ExtendedKey
+	params    *chaincfg.Params
+	network   Network
+	gapLimit  uint32
+	accounts  map[uint32]*Account
+}
+
+// NewAccountManager derives the master key from config.Mnemonic and returns
+// an AccountManager that derives accounts from it on demand. gapLimit
+// defaults to DefaultGapLimit when zero.
+func NewAccountManager(config *Config, gapLimit uint32) (*AccountManager, error) {
+	if config.Mnemonic == "" || !validateMnemonic(config.Mnemonic) {
+		return nil, errors.New(ErrInvalidMnemonic)
+	}
+	params, err := selectNetworkParams(config.Network)
+	if err != nil {
+		return nil, err
+	}
+	if gapLimit == 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	seed := bip39.NewSeed(config.Mnemonic, "")
+	masterKey, err := generateMasterKey(seed, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountManager{
+		masterKey: masterKey,
+		params:    params,
+		network:   config.Network,
+		gapLimit:  gapLimit,
+		accounts:  make(map[uint32]*Account),
+	}, nil
+}
+
+// Account returns the account at index, deriving and caching it on first
+// use.
+func (m *AccountManager) Account(index uint32) (*Account, error) {
+	if acc, ok := m.accounts[index]; ok {
+		return acc, nil
+	}
+
+	path := fmt.Sprintf("m/84'/%d'/%d'", coinType(m.network), index)
+	accountKey, err := DeriveKeyFromPath(m.masterKey, path)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := &Account{
+		index:       index,
+		path:        path,
+		accountKey:  accountKey,
+		params:      m.params,
+		gapLimit:    m.gapLimit,
+		highestUsed: map[uint32]int64{BranchExternal: -1, BranchInternal: -1},
+	}
+	m.accounts[index] = acc
+	return acc, nil
+}
+
+// coinType maps a Network to its BIP44 registered coin type, matching the
+// coin component already baked into DefaultMainnetPath/DefaultTestnetPath.
+func coinType(network Network) uint32 {
+	if network == NetworkTestnet {
+		return 1
+	}
+	return 0
+}
+
+// Account is one BIP44/BIP84 account, m/84'/coin'/account', able to derive
+// its external (receive) and internal (change) address chains.
+type Account struct {
+	index       uint32
+	path        string
+	accountKey  *hdkeychain.ExtendedKey
+	params      *chaincfg.Params
+	gapLimit    uint32
+	highestUsed map[uint32]int64
+}
+
+// Receive derives the external (branch 0) address at index.
+func (a *Account) Receive(index uint32) (*btcutil.AddressWitnessPubKeyHash, error) {
+	return a.deriveAddress(BranchExternal, index)
+}
+
+// Change derives the internal (branch 1) address at index.
+func (a *Account) Change(index uint32) (*btcutil.AddressWitnessPubKeyHash, error) {
+	return a.deriveAddress(BranchInternal, index)
+}
+
+// NextUnused returns the address on branch just past the highest index Scan
+// recorded as used (index 0 if none was), along with its index.
+func (a *Account) NextUnused(branch uint32) (*btcutil.AddressWitnessPubKeyHash, uint32, error) {
+	index := uint32(a.highestUsed[branch] + 1)
+	addr, err := a.deriveAddress(branch, index)
+	return addr, index, err
+}
+
+// Scan walks each branch's addresses from index 0, calling lookup for each,
+// until gapLimit consecutive unused addresses are seen - the standard BIP44
+// HD recovery algorithm. It records the highest used index per branch so
+// NextUnused reflects what was found.
+func (a *Account) Scan(ctx context.Context, lookup func(addr string) (bool, error)) error {
+	for _, branch := range []uint32{BranchExternal, BranchInternal} {
+		if err := a.scanBranch(ctx, branch, lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Account) scanBranch(ctx context.Context, branch uint32, lookup func(addr string) (bool, error)) error {
+	var unused uint32
+	for index := uint32(0); unused < a.gapLimit; index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		addr, err := a.deriveAddress(branch, index)
+		if err != nil {
+			return err
+		}
+		used, err := lookup(addr.EncodeAddress())
+		if err != nil {
+			return err
+		}
+
+		if used {
+			a.highestUsed[branch] = int64(index)
+			unused = 0
+			continue
+		}
+		unused++
+	}
+	return nil
+}
+
+func (a *Account) deriveAddress(branch, index uint32) (*btcutil.AddressWitnessPubKeyHash, error) {
+	branchKey, err := a.accountKey.Derive(branch)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrKeyDerivation, err)
+	}
+	childKey, err := branchKey.Derive(index)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrKeyDerivation, err)
+	}
+	return deriveAddressFromPublicKey(childKey, a.params)
+}
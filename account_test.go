@@ -0,0 +1,63 @@
+package segwit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewAccountManager(t *testing.T) {
+	mnemonic := createTestMnemonic(t)
+
+	mgr, err := NewAccountManager(&Config{Mnemonic: mnemonic, Network: NetworkMainnet}, 0)
+	assert.NoError(t, err)
+	assert.NotNil(t, mgr)
+
+	_, err = NewAccountManager(&Config{Mnemonic: "", Network: NetworkMainnet}, 0)
+	assert.EqualError(t, err, ErrInvalidMnemonic)
+}
+
+func Test_Account_ReceiveAndChange(t *testing.T) {
+	mnemonic := createTestMnemonic(t)
+	mgr, err := NewAccountManager(&Config{Mnemonic: mnemonic, Network: NetworkMainnet}, 0)
+	assert.NoError(t, err)
+
+	acc, err := mgr.Account(0)
+	assert.NoError(t, err)
+
+	receive, err := acc.Receive(0)
+	assert.NoError(t, err)
+	assert.NotNil(t, receive)
+
+	change, err := acc.Change(0)
+	assert.NoError(t, err)
+	assert.NotNil(t, change)
+	assert.NotEqual(t, receive.EncodeAddress(), change.EncodeAddress())
+
+	sameAccount, err := mgr.Account(0)
+	assert.NoError(t, err)
+	assert.Same(t, acc, sameAccount, "Account should cache and return the same instance")
+}
+
+func Test_Account_Scan(t *testing.T) {
+	mnemonic := createTestMnemonic(t)
+	mgr, err := NewAccountManager(&Config{Mnemonic: mnemonic, Network: NetworkMainnet}, 3)
+	assert.NoError(t, err)
+
+	acc, err := mgr.Account(0)
+	assert.NoError(t, err)
+
+	usedAddr, err := acc.Receive(2)
+	assert.NoError(t, err)
+
+	err = acc.Scan(context.Background(), func(addr string) (bool, error) {
+		return addr == usedAddr.EncodeAddress(), nil
+	})
+	assert.NoError(t, err)
+
+	next, index, err := acc.NextUnused(BranchExternal)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), index)
+	assert.NotNil(t, next)
+}
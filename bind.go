@@ -0,0 +1,149 @@
+package segwit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"github.com/ariden83/segwit.go/chain"
+)
+
+const (
+	ErrNoBackend          = "wallet has no chain backend bound; call Bind first"
+	ErrNoSpendableUTXOs   = "no spendable UTXOs for this address"
+	ErrInsufficientFunds  = "insufficient funds to cover amount and fee"
+	ErrInvalidSendAmount  = "send amount must be positive"
+	ErrInvalidSendFeeRate = "fee rate must be positive"
+)
+
+// Bind attaches backend as the wallet's source of chain data, enabling
+// Balance, UTXOs, and Send. Wallets are not bound to a backend by default,
+// keeping the cryptographic core usable offline.
+func (s *Wallet) Bind(backend chain.Backend) {
+	s.backend = backend
+}
+
+// Balance returns the wallet address's current balance, as reported by the
+// bound backend.
+func (s *Wallet) Balance() (btcutil.Amount, error) {
+	if s.backend == nil {
+		return 0, errors.New(ErrNoBackend)
+	}
+	return s.backend.GetBalance(s.address)
+}
+
+// UTXOs returns the wallet address's unspent outputs, as reported by the
+// bound backend.
+func (s *Wallet) UTXOs() ([]chain.UTXO, error) {
+	if s.backend == nil {
+		return nil, errors.New(ErrNoBackend)
+	}
+	return s.backend.ListUTXOs(s.address)
+}
+
+// Send builds, signs, and broadcasts a transaction paying amount to to,
+// selecting inputs from the wallet's own UTXOs with a simple largest-first
+// strategy and returning any change to the wallet's own address. It signs
+// directly with the wallet's single key rather than going through the
+// segwit/tx package's PSBT builder, since tx already imports this package
+// and importing it back here would create a cycle.
+func (s *Wallet) Send(to string, amount btcutil.Amount, feeRateSatPerVB float64) (chainhash.Hash, error) {
+	if s.backend == nil {
+		return chainhash.Hash{}, errors.New(ErrNoBackend)
+	}
+	if amount <= 0 {
+		return chainhash.Hash{}, errors.New(ErrInvalidSendAmount)
+	}
+	if feeRateSatPerVB <= 0 {
+		return chainhash.Hash{}, errors.New(ErrInvalidSendFeeRate)
+	}
+
+	destAddr, err := btcutil.DecodeAddress(to, s.params)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	destScript, err := txscript.PayToAddrScript(destAddr)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	utxos, err := s.backend.ListUTXOs(s.address)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	if len(utxos) == 0 {
+		return chainhash.Hash{}, errors.New(ErrNoSpendableUTXOs)
+	}
+
+	selected, _, change, err := selectUTXOs(utxos, amount, feeRateSatPerVB)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	pkScript, err := txscript.PayToAddrScript(s.address)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	for _, u := range selected {
+		msgTx.AddTxIn(wire.NewTxIn(&u.Outpoint, nil, nil))
+	}
+	msgTx.AddTxOut(wire.NewTxOut(int64(amount), destScript))
+	if change > 0 {
+		msgTx.AddTxOut(wire.NewTxOut(int64(change), pkScript))
+	}
+
+	privKey, err := s.extendedKey.ECPrivKey()
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, u := range selected {
+		fetcher.AddPrevOut(u.Outpoint, &wire.TxOut{Value: int64(u.Amount), PkScript: pkScript})
+	}
+	sigHashes := txscript.NewTxSigHashes(msgTx, fetcher)
+
+	for i, u := range selected {
+		witness, err := txscript.WitnessSignature(msgTx, sigHashes, i, int64(u.Amount), pkScript, txscript.SigHashAll, privKey, true)
+		if err != nil {
+			return chainhash.Hash{}, fmt.Errorf("%s: %w", ErrKeyDerivation, err)
+		}
+		msgTx.TxIn[i].Witness = witness
+	}
+
+	return s.backend.BroadcastTx(msgTx)
+}
+
+// selectUTXOs picks utxos, largest amount first, until their sum covers
+// amount plus the fee implied by feeRateSatPerVB, returning any leftover as
+// change.
+func selectUTXOs(utxos []chain.UTXO, amount btcutil.Amount, feeRateSatPerVB float64) (selected []chain.UTXO, fee, change btcutil.Amount, err error) {
+	const (
+		inputVBytes  = 68
+		overheadVB   = 11
+		outputVBytes = 31
+	)
+
+	sorted := make([]chain.UTXO, len(utxos))
+	copy(sorted, utxos)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+
+	var accumulated btcutil.Amount
+	for _, u := range sorted {
+		selected = append(selected, u)
+		accumulated += u.Amount
+		fee = btcutil.Amount(feeRateSatPerVB * float64(overheadVB+outputVBytes+inputVBytes*len(selected)))
+		if accumulated >= amount+fee {
+			change = accumulated - amount - fee
+			return selected, fee, change, nil
+		}
+	}
+	return nil, 0, 0, errors.New(ErrInsufficientFunds)
+}
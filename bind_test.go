@@ -0,0 +1,108 @@
+package segwit
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ariden83/segwit.go/chain"
+)
+
+// fakeBackend is an in-memory chain.Backend used to test Wallet.Bind,
+// Balance, UTXOs, and Send without a real node or indexer.
+type fakeBackend struct {
+	utxos        []chain.UTXO
+	balance      btcutil.Amount
+	broadcastTx  *wire.MsgTx
+	broadcastErr error
+}
+
+func (f *fakeBackend) GetBalance(addr btcutil.Address) (btcutil.Amount, error) {
+	return f.balance, nil
+}
+
+func (f *fakeBackend) ListUTXOs(addr btcutil.Address) ([]chain.UTXO, error) {
+	return f.utxos, nil
+}
+
+func (f *fakeBackend) GetTxHistory(addr btcutil.Address, fromHeight int32) ([]chain.TxRecord, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) BroadcastTx(tx *wire.MsgTx) (chainhash.Hash, error) {
+	f.broadcastTx = tx
+	return tx.TxHash(), f.broadcastErr
+}
+
+func (f *fakeBackend) SubscribeAddress(addr btcutil.Address) (<-chan chain.Notification, error) {
+	return make(chan chain.Notification), nil
+}
+
+func Test_Wallet_Balance_NoBackend(t *testing.T) {
+	wallet := createTestWallet(t, NetworkMainnet, "")
+
+	_, err := wallet.Balance()
+	assert.EqualError(t, err, ErrNoBackend)
+}
+
+func Test_Wallet_Balance_And_UTXOs(t *testing.T) {
+	wallet := createTestWallet(t, NetworkMainnet, "")
+	pkScript, err := txscript.PayToAddrScript(wallet.Address())
+	assert.NoError(t, err)
+
+	backend := &fakeBackend{
+		balance: 50000,
+		utxos: []chain.UTXO{
+			{Outpoint: wire.OutPoint{Index: 0}, Amount: 50000, PkScript: pkScript, Height: 100},
+		},
+	}
+	wallet.Bind(backend)
+
+	balance, err := wallet.Balance()
+	assert.NoError(t, err)
+	assert.Equal(t, btcutil.Amount(50000), balance)
+
+	utxos, err := wallet.UTXOs()
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 1)
+}
+
+func Test_Wallet_Send(t *testing.T) {
+	wallet := createTestWallet(t, NetworkMainnet, "")
+	pkScript, err := txscript.PayToAddrScript(wallet.Address())
+	assert.NoError(t, err)
+
+	backend := &fakeBackend{
+		utxos: []chain.UTXO{
+			{Outpoint: wire.OutPoint{Index: 0}, Amount: 100000, PkScript: pkScript, Height: 100},
+		},
+	}
+	wallet.Bind(backend)
+
+	destWallet := createTestWallet(t, NetworkMainnet, "")
+
+	txid, err := wallet.Send(destWallet.AddressHex(), 50000, 10)
+	assert.NoError(t, err)
+	assert.NotEqual(t, chainhash.Hash{}, txid)
+	assert.NotNil(t, backend.broadcastTx)
+	assert.Len(t, backend.broadcastTx.TxIn, 1)
+}
+
+func Test_Wallet_Send_NoBackend(t *testing.T) {
+	wallet := createTestWallet(t, NetworkMainnet, "")
+
+	_, err := wallet.Send("bc1qexampleaddress", 1000, 10)
+	assert.EqualError(t, err, ErrNoBackend)
+}
+
+func Test_Wallet_Send_InvalidAmount(t *testing.T) {
+	wallet := createTestWallet(t, NetworkMainnet, "")
+	wallet.Bind(&fakeBackend{})
+
+	_, err := wallet.Send("bc1qexampleaddress", 0, 10)
+	assert.EqualError(t, err, ErrInvalidSendAmount)
+}
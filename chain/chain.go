@@ -0,0 +1,63 @@
+// Package chain defines a pluggable backend for balances, UTXOs, transaction
+// history, broadcast, and address notifications, so the cryptographic core
+// in segwit stays independent of any particular node or indexer.
+package chain
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// UTXO is a spendable output reported by a Backend.
+type UTXO struct {
+	Outpoint wire.OutPoint
+	Amount   btcutil.Amount
+	PkScript []byte
+	Height   int32
+}
+
+// TxRecord is one entry in an address's transaction history.
+type TxRecord struct {
+	Hash   chainhash.Hash
+	Height int32
+	Amount btcutil.Amount
+}
+
+// NotificationType distinguishes the kinds of events SubscribeAddress emits.
+type NotificationType int
+
+const (
+	// NotificationUnconfirmed reports a new mempool transaction touching
+	// the subscribed address.
+	NotificationUnconfirmed NotificationType = iota
+	// NotificationConfirmed reports a transaction touching the subscribed
+	// address that has been mined.
+	NotificationConfirmed
+)
+
+// Notification is a single event delivered by SubscribeAddress.
+type Notification struct {
+	Type   NotificationType
+	Tx     chainhash.Hash
+	Height int32
+}
+
+// Backend is a pluggable source of chain data for a single P2WPKH address at
+// a time, keeping backend choice orthogonal to the wallet's cryptographic
+// core so tests can use a fake implementation.
+type Backend interface {
+	GetBalance(addr btcutil.Address) (btcutil.Amount, error)
+	ListUTXOs(addr btcutil.Address) ([]UTXO, error)
+	GetTxHistory(addr btcutil.Address, fromHeight int32) ([]TxRecord, error)
+	BroadcastTx(tx *wire.MsgTx) (chainhash.Hash, error)
+	SubscribeAddress(addr btcutil.Address) (<-chan Notification, error)
+}
+
+// payToAddrScript is a small shared helper the backend implementations use
+// to build addr's output script; it returns txscript.PayToAddrScript's
+// result unchanged.
+func payToAddrScript(addr btcutil.Address) ([]byte, error) {
+	return txscript.PayToAddrScript(addr)
+}
@@ -0,0 +1,338 @@
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	ErrElectrumDial     = "electrum: failed to connect"
+	ErrElectrumRequest  = "electrum: request failed"
+	ErrElectrumResponse = "electrum: unexpected response"
+
+	electrumDialTimeout = 10 * time.Second
+)
+
+// ElectrumClient is a minimal JSON-over-TCP client for the ElectrumX
+// stratum-style protocol, identifying addresses by their scripthash
+// (reversed sha256 of the output script) as the protocol requires. A single
+// goroutine reads every line off the wire and dispatches it by ID to the
+// pending call it answers, or by scripthash to a subscriber's channel, so
+// call and SubscribeAddress can be used concurrently on the same
+// connection without racing for the next line.
+type ElectrumClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	nextID  uint64
+
+	mu      sync.Mutex
+	pending map[uint64]chan electrumResponse
+	subs    map[string]chan Notification
+}
+
+// DialElectrum opens a TCP connection to an ElectrumX server at addr
+// (host:port) and starts its read loop.
+func DialElectrum(addr string) (*ElectrumClient, error) {
+	conn, err := net.DialTimeout("tcp", addr, electrumDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrElectrumDial, err)
+	}
+	c := &ElectrumClient{
+		conn:    conn,
+		pending: make(map[uint64]chan electrumResponse),
+		subs:    make(map[string]chan Notification),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+type electrumRequest struct {
+	ID     uint64        `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+type electrumResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// electrumMessage is decoded first for every line so readLoop can tell a
+// call's response (has "id") from a subscription notification (has
+// "method" instead).
+type electrumMessage struct {
+	ID     *uint64         `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// readLoop owns the connection's bufio.Scanner for the client's lifetime,
+// routing each line to the call waiting on its ID or the subscriber
+// watching its scripthash. It is the only goroutine that ever reads from
+// the connection.
+func (c *ElectrumClient) readLoop() {
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+
+		var msg electrumMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			continue
+		}
+
+		if msg.ID != nil {
+			var resp electrumResponse
+			if err := json.Unmarshal(line, &resp); err != nil {
+				continue
+			}
+			c.mu.Lock()
+			ch, ok := c.pending[resp.ID]
+			if ok {
+				delete(c.pending, resp.ID)
+			}
+			c.mu.Unlock()
+			if ok {
+				ch <- resp
+			}
+			continue
+		}
+
+		if msg.Method == "blockchain.scripthash.subscribe" {
+			var params []string
+			if err := json.Unmarshal(msg.Params, &params); err != nil || len(params) == 0 {
+				continue
+			}
+			c.mu.Lock()
+			ch, ok := c.subs[params[0]]
+			c.mu.Unlock()
+			if ok {
+				ch <- Notification{Type: NotificationUnconfirmed}
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	for hash, ch := range c.subs {
+		close(ch)
+		delete(c.subs, hash)
+	}
+}
+
+// call sends a single stratum request and waits for readLoop to deliver the
+// response with the matching ID.
+func (c *ElectrumClient) call(method string, params []interface{}, out interface{}) error {
+	id := atomic.AddUint64(&c.nextID, 1)
+	respCh := make(chan electrumResponse, 1)
+
+	c.mu.Lock()
+	c.pending[id] = respCh
+	c.mu.Unlock()
+
+	req := electrumRequest{ID: id, Method: method, Params: params}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return err
+	}
+
+	c.writeMu.Lock()
+	_, err = c.conn.Write(append(payload, '\n'))
+	c.writeMu.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return fmt.Errorf("%s: %w", ErrElectrumRequest, err)
+	}
+
+	resp, ok := <-respCh
+	if !ok {
+		return fmt.Errorf("%s: connection closed", ErrElectrumRequest)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s: %v", ErrElectrumRequest, resp.Error)
+	}
+	if out != nil {
+		if err := json.Unmarshal(resp.Result, out); err != nil {
+			return fmt.Errorf("%s: %w", ErrElectrumResponse, err)
+		}
+	}
+	return nil
+}
+
+// scripthash computes the ElectrumX scripthash for addr: the reversed
+// sha256 of its output script, hex-encoded.
+func scripthash(addr btcutil.Address) (string, error) {
+	script, err := payToAddrScript(addr)
+	if err != nil {
+		return "", err
+	}
+	sum := chainhash.HashB(script)
+	for i, j := 0, len(sum)-1; i < j; i, j = i+1, j-1 {
+		sum[i], sum[j] = sum[j], sum[i]
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+type electrumBalance struct {
+	Confirmed   int64 `json:"confirmed"`
+	Unconfirmed int64 `json:"unconfirmed"`
+}
+
+// GetBalance returns addr's confirmed plus unconfirmed balance.
+func (c *ElectrumClient) GetBalance(addr btcutil.Address) (btcutil.Amount, error) {
+	hash, err := scripthash(addr)
+	if err != nil {
+		return 0, err
+	}
+	var bal electrumBalance
+	if err := c.call("blockchain.scripthash.get_balance", []interface{}{hash}, &bal); err != nil {
+		return 0, err
+	}
+	return btcutil.Amount(bal.Confirmed + bal.Unconfirmed), nil
+}
+
+type electrumUTXO struct {
+	TxHash string `json:"tx_hash"`
+	TxPos  uint32 `json:"tx_pos"`
+	Height int32  `json:"height"`
+	Value  int64  `json:"value"`
+}
+
+// ListUTXOs returns every unspent output owned by addr.
+func (c *ElectrumClient) ListUTXOs(addr btcutil.Address) ([]UTXO, error) {
+	hash, err := scripthash(addr)
+	if err != nil {
+		return nil, err
+	}
+	var raw []electrumUTXO
+	if err := c.call("blockchain.scripthash.listunspent", []interface{}{hash}, &raw); err != nil {
+		return nil, err
+	}
+
+	pkScript, err := payToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]UTXO, len(raw))
+	for i, u := range raw {
+		txHash, err := chainhash.NewHashFromStr(u.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrElectrumResponse, err)
+		}
+		height := u.Height
+		if height <= 0 {
+			height = -1
+		}
+		utxos[i] = UTXO{
+			Outpoint: wire.OutPoint{Hash: *txHash, Index: u.TxPos},
+			Amount:   btcutil.Amount(u.Value),
+			PkScript: pkScript,
+			Height:   height,
+		}
+	}
+	return utxos, nil
+}
+
+type electrumHistoryEntry struct {
+	TxHash string `json:"tx_hash"`
+	Height int32  `json:"height"`
+}
+
+// GetTxHistory returns addr's transaction history, filtered to entries
+// confirmed at or after fromHeight (unconfirmed entries, height <= 0, are
+// always included). Amount is left zero, since ElectrumX's history method
+// does not report per-entry value; callers needing it should fetch the raw
+// transaction separately.
+func (c *ElectrumClient) GetTxHistory(addr btcutil.Address, fromHeight int32) ([]TxRecord, error) {
+	hash, err := scripthash(addr)
+	if err != nil {
+		return nil, err
+	}
+	var raw []electrumHistoryEntry
+	if err := c.call("blockchain.scripthash.get_history", []interface{}{hash}, &raw); err != nil {
+		return nil, err
+	}
+
+	var history []TxRecord
+	for _, e := range raw {
+		if e.Height > 0 && e.Height < fromHeight {
+			continue
+		}
+		txHash, err := chainhash.NewHashFromStr(e.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrElectrumResponse, err)
+		}
+		height := e.Height
+		if height <= 0 {
+			height = -1
+		}
+		history = append(history, TxRecord{Hash: *txHash, Height: height})
+	}
+	return history, nil
+}
+
+// BroadcastTx submits tx's raw hex encoding to the server.
+func (c *ElectrumClient) BroadcastTx(tx *wire.MsgTx) (chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	var txid string
+	if err := c.call("blockchain.transaction.broadcast", []interface{}{hex.EncodeToString(buf.Bytes())}, &txid); err != nil {
+		return chainhash.Hash{}, err
+	}
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	return *hash, nil
+}
+
+// SubscribeAddress subscribes to addr's scripthash and translates each
+// ElectrumX status-change notification into a Notification. ElectrumX only
+// reports that *something* changed (a new status hash), not which
+// transaction, so Notification.Tx is left zero; callers should follow up
+// with ListUTXOs or GetTxHistory to see what changed. The subscription is
+// delivered by readLoop, so SubscribeAddress can be called while other
+// goroutines are using call concurrently on the same connection.
+func (c *ElectrumClient) SubscribeAddress(addr btcutil.Address) (<-chan Notification, error) {
+	hash, err := scripthash(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Notification)
+	c.mu.Lock()
+	c.subs[hash] = ch
+	c.mu.Unlock()
+
+	if err := c.call("blockchain.scripthash.subscribe", []interface{}{hash}, nil); err != nil {
+		c.mu.Lock()
+		delete(c.subs, hash)
+		c.mu.Unlock()
+		return nil, err
+	}
+	return ch, nil
+}
@@ -0,0 +1,138 @@
+package chain
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAddr(t *testing.T) btcutil.Address {
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	return addr
+}
+
+// Test_Scripthash_IsReversedSha256 guards the one piece of pure logic
+// ElectrumX's protocol hinges on: the scripthash is sha256(script) with its
+// bytes reversed, not sha256 encoded directly.
+func Test_Scripthash_IsReversedSha256(t *testing.T) {
+	addr := testAddr(t)
+	hash, err := scripthash(addr)
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64, "scripthash should be a hex-encoded 32-byte digest")
+
+	script, err := payToAddrScript(addr)
+	assert.NoError(t, err)
+	sum := sha256.Sum256(script)
+	reversed := make([]byte, len(sum))
+	for i, b := range sum {
+		reversed[len(sum)-1-i] = b
+	}
+	assert.Equal(t, hex.EncodeToString(reversed), hash)
+}
+
+// newTestClient wires an ElectrumClient to one end of an in-memory pipe,
+// starting its read loop, and returns the other end for a fake server to
+// drive.
+func newTestClient() (*ElectrumClient, net.Conn) {
+	clientConn, serverConn := net.Pipe()
+	c := &ElectrumClient{
+		conn:    clientConn,
+		pending: make(map[uint64]chan electrumResponse),
+		subs:    make(map[string]chan Notification),
+	}
+	go c.readLoop()
+	return c, serverConn
+}
+
+// Test_Call_And_Subscribe_DoNotRace reproduces the scenario the review
+// flagged: a subscription active on the connection while concurrent calls
+// are in flight. Before the single-reader fix, the subscriber goroutine and
+// call() raced for the next line off the same bufio.Scanner, so a call's
+// response could be silently stolen by the subscriber. Run with -race to
+// catch the data race directly; the assertions below catch the resulting
+// misrouted/hung response even without it.
+func Test_Call_And_Subscribe_DoNotRace(t *testing.T) {
+	client, server := newTestClient()
+	defer client.conn.Close()
+	defer server.Close()
+
+	addr := testAddr(t)
+	hash, err := scripthash(addr)
+	assert.NoError(t, err)
+
+	var serverWG sync.WaitGroup
+	serverWG.Add(1)
+	go func() {
+		defer serverWG.Done()
+		scanner := bufio.NewScanner(server)
+		for scanner.Scan() {
+			var req electrumRequest
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+				return
+			}
+			switch req.Method {
+			case "blockchain.scripthash.subscribe":
+				writeLine(server, map[string]interface{}{"id": req.ID, "result": true})
+				// Interleave a burst of notifications with ordinary call
+				// responses below, the way a real server would.
+				for i := 0; i < 5; i++ {
+					writeLine(server, map[string]interface{}{
+						"method": "blockchain.scripthash.subscribe",
+						"params": []string{hash},
+					})
+				}
+			case "blockchain.scripthash.get_balance":
+				writeLine(server, map[string]interface{}{
+					"id":     req.ID,
+					"result": map[string]int64{"confirmed": int64(req.ID), "unconfirmed": 0},
+				})
+			}
+		}
+	}()
+
+	sub, err := client.SubscribeAddress(addr)
+	assert.NoError(t, err)
+
+	notifications := make(chan struct{}, 64)
+	go func() {
+		for range sub {
+			notifications <- struct{}{}
+		}
+	}()
+
+	const calls = 20
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetBalance(addr)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case <-notifications:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected at least one notification to be delivered")
+	}
+
+	server.Close()
+	serverWG.Wait()
+}
+
+func writeLine(conn net.Conn, v interface{}) {
+	payload, _ := json.Marshal(v)
+	conn.Write(append(payload, '\n'))
+}
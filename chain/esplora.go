@@ -0,0 +1,240 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	ErrEsploraRequest  = "esplora request failed"
+	ErrEsploraResponse = "unexpected esplora response"
+
+	esploraPollInterval = 15 * time.Second
+)
+
+// EsploraClient talks to a Blockstream-style Esplora HTTP API
+// (e.g. https://blockstream.info/api).
+type EsploraClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewEsploraClient returns a client rooted at baseURL (no trailing slash).
+func NewEsploraClient(baseURL string) *EsploraClient {
+	return &EsploraClient{BaseURL: baseURL, HTTPClient: http.DefaultClient}
+}
+
+type esploraAddressStats struct {
+	ChainStats struct {
+		FundedTxoSum int64 `json:"funded_txo_sum"`
+		SpentTxoSum  int64 `json:"spent_txo_sum"`
+	} `json:"chain_stats"`
+	MempoolStats struct {
+		FundedTxoSum int64 `json:"funded_txo_sum"`
+		SpentTxoSum  int64 `json:"spent_txo_sum"`
+	} `json:"mempool_stats"`
+}
+
+// GetBalance returns the confirmed plus unconfirmed balance for addr.
+func (c *EsploraClient) GetBalance(addr btcutil.Address) (btcutil.Amount, error) {
+	var stats esploraAddressStats
+	if err := c.get(fmt.Sprintf("/address/%s", addr.EncodeAddress()), &stats); err != nil {
+		return 0, err
+	}
+	total := stats.ChainStats.FundedTxoSum - stats.ChainStats.SpentTxoSum +
+		stats.MempoolStats.FundedTxoSum - stats.MempoolStats.SpentTxoSum
+	return btcutil.Amount(total), nil
+}
+
+type esploraUTXO struct {
+	Txid   string `json:"txid"`
+	Vout   uint32 `json:"vout"`
+	Value  int64  `json:"value"`
+	Status struct {
+		Confirmed   bool  `json:"confirmed"`
+		BlockHeight int32 `json:"block_height"`
+	} `json:"status"`
+}
+
+// ListUTXOs returns every unspent output owned by addr.
+func (c *EsploraClient) ListUTXOs(addr btcutil.Address) ([]UTXO, error) {
+	var raw []esploraUTXO
+	if err := c.get(fmt.Sprintf("/address/%s/utxo", addr.EncodeAddress()), &raw); err != nil {
+		return nil, err
+	}
+
+	pkScript, err := payToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]UTXO, len(raw))
+	for i, u := range raw {
+		hash, err := chainhash.NewHashFromStr(u.Txid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrEsploraResponse, err)
+		}
+		height := int32(-1)
+		if u.Status.Confirmed {
+			height = u.Status.BlockHeight
+		}
+		utxos[i] = UTXO{
+			Outpoint: wire.OutPoint{Hash: *hash, Index: u.Vout},
+			Amount:   btcutil.Amount(u.Value),
+			PkScript: pkScript,
+			Height:   height,
+		}
+	}
+	return utxos, nil
+}
+
+type esploraTx struct {
+	Txid   string `json:"txid"`
+	Status struct {
+		Confirmed   bool  `json:"confirmed"`
+		BlockHeight int32 `json:"block_height"`
+	} `json:"status"`
+	Vout []struct {
+		ScriptPubKeyAddress string `json:"scriptpubkey_address"`
+		Value               int64  `json:"value"`
+	} `json:"vout"`
+}
+
+// GetTxHistory returns addr's transaction history, filtered to entries
+// confirmed at or after fromHeight (unconfirmed entries are always
+// included).
+func (c *EsploraClient) GetTxHistory(addr btcutil.Address, fromHeight int32) ([]TxRecord, error) {
+	var raw []esploraTx
+	if err := c.get(fmt.Sprintf("/address/%s/txs", addr.EncodeAddress()), &raw); err != nil {
+		return nil, err
+	}
+
+	var history []TxRecord
+	for _, t := range raw {
+		if t.Status.Confirmed && t.Status.BlockHeight < fromHeight {
+			continue
+		}
+		hash, err := chainhash.NewHashFromStr(t.Txid)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrEsploraResponse, err)
+		}
+		height := int32(-1)
+		if t.Status.Confirmed {
+			height = t.Status.BlockHeight
+		}
+
+		var amount btcutil.Amount
+		for _, out := range t.Vout {
+			if out.ScriptPubKeyAddress == addr.EncodeAddress() {
+				amount += btcutil.Amount(out.Value)
+			}
+		}
+		history = append(history, TxRecord{Hash: *hash, Height: height, Amount: amount})
+	}
+	return history, nil
+}
+
+// BroadcastTx submits tx's raw hex encoding to the Esplora node.
+func (c *EsploraClient) BroadcastTx(tx *wire.MsgTx) (chainhash.Hash, error) {
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return chainhash.Hash{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+"/tx", strings.NewReader(hex.EncodeToString(buf.Bytes())))
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("%s: %w", ErrEsploraRequest, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return chainhash.Hash{}, fmt.Errorf("%s: %s", ErrEsploraRequest, strings.TrimSpace(string(body)))
+	}
+	hash, err := chainhash.NewHashFromStr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return chainhash.Hash{}, err
+	}
+	return *hash, nil
+}
+
+// SubscribeAddress polls the address's UTXO set on an interval, since
+// Esplora's plain HTTP API has no push notifications, and emits a
+// Notification whenever a new confirmed or unconfirmed UTXO appears.
+func (c *EsploraClient) SubscribeAddress(addr btcutil.Address) (<-chan Notification, error) {
+	ch := make(chan Notification)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		defer close(ch)
+		seen := make(map[string]bool)
+		ticker := time.NewTicker(esploraPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				utxos, err := c.ListUTXOs(addr)
+				if err != nil {
+					continue
+				}
+				for _, u := range utxos {
+					key := u.Outpoint.Hash.String() + ":" + strconv.Itoa(int(u.Outpoint.Index))
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					notifType := NotificationUnconfirmed
+					if u.Height >= 0 {
+						notifType = NotificationConfirmed
+					}
+					ch <- Notification{Type: notifType, Tx: u.Outpoint.Hash, Height: u.Height}
+				}
+			}
+		}
+	}()
+
+	// cancel is intentionally unused by callers today; SubscribeAddress has
+	// no Unsubscribe in the Backend interface, so the goroutine runs for
+	// the process lifetime like the other backends' subscriptions.
+	_ = cancel
+	return ch, nil
+}
+
+func (c *EsploraClient) get(path string, out interface{}) error {
+	resp, err := c.HTTPClient.Get(c.BaseURL + path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrEsploraRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s: status %d: %s", ErrEsploraRequest, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", ErrEsploraResponse, err)
+	}
+	return nil
+}
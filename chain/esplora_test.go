@@ -0,0 +1,83 @@
+package chain
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_Esplora_GetBalance_SumsChainAndMempool guards the balance math:
+// confirmed plus unconfirmed funded minus spent, across both chain_stats
+// and mempool_stats.
+func Test_Esplora_GetBalance_SumsChainAndMempool(t *testing.T) {
+	addr := testAddr(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"chain_stats": {"funded_txo_sum": 100000, "spent_txo_sum": 40000},
+			"mempool_stats": {"funded_txo_sum": 5000, "spent_txo_sum": 1000}
+		}`)
+	}))
+	defer server.Close()
+
+	client := NewEsploraClient(server.URL)
+	balance, err := client.GetBalance(addr)
+	assert.NoError(t, err)
+	assert.Equal(t, btcutil.Amount(100000-40000+5000-1000), balance)
+}
+
+// Test_Esplora_ListUTXOs_MapsConfirmedHeight guards the confirmed/
+// unconfirmed height mapping: unconfirmed UTXOs must report height -1, not
+// the zero-value block_height an unconfirmed entry's JSON carries.
+func Test_Esplora_ListUTXOs_MapsConfirmedHeight(t *testing.T) {
+	addr := testAddr(t)
+	txid := "1111111111111111111111111111111111111111111111111111111111111111"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[
+			{"txid": "%s", "vout": 0, "value": 50000, "status": {"confirmed": true, "block_height": 800000}},
+			{"txid": "%s", "vout": 1, "value": 1000, "status": {"confirmed": false, "block_height": 0}}
+		]`, txid, txid)
+	}))
+	defer server.Close()
+
+	client := NewEsploraClient(server.URL)
+	utxos, err := client.ListUTXOs(addr)
+	assert.NoError(t, err)
+	assert.Len(t, utxos, 2)
+	assert.Equal(t, int32(800000), utxos[0].Height)
+	assert.Equal(t, int32(-1), utxos[1].Height)
+}
+
+// Test_Esplora_GetTxHistory_FiltersByHeight guards the fromHeight filter:
+// confirmed entries below fromHeight are dropped, but unconfirmed entries
+// (height <= 0) always pass through regardless of fromHeight, and only
+// outputs paying the queried address count toward Amount.
+func Test_Esplora_GetTxHistory_FiltersByHeight(t *testing.T) {
+	addr := testAddr(t)
+	other := "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+	txOld := "2222222222222222222222222222222222222222222222222222222222222222"
+	txNew := "3333333333333333333333333333333333333333333333333333333333333333"
+	txMempool := "4444444444444444444444444444444444444444444444444444444444444444"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[
+			{"txid": "%s", "status": {"confirmed": true, "block_height": 100}, "vout": [{"scriptpubkey_address": "%s", "value": 1000}]},
+			{"txid": "%s", "status": {"confirmed": true, "block_height": 900}, "vout": [{"scriptpubkey_address": "%s", "value": 2000}, {"scriptpubkey_address": "%s", "value": 500}]},
+			{"txid": "%s", "status": {"confirmed": false, "block_height": 0}, "vout": [{"scriptpubkey_address": "%s", "value": 3000}]}
+		]`, txOld, addr.EncodeAddress(), txNew, addr.EncodeAddress(), other, txMempool, addr.EncodeAddress())
+	}))
+	defer server.Close()
+
+	client := NewEsploraClient(server.URL)
+	history, err := client.GetTxHistory(addr, 500)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2, "the height-100 entry should be filtered out, the mempool entry kept regardless")
+
+	assert.Equal(t, int32(900), history[0].Height)
+	assert.Equal(t, btcutil.Amount(2000), history[0].Amount, "only the output paying addr should count")
+	assert.Equal(t, int32(-1), history[1].Height)
+	assert.Equal(t, btcutil.Amount(3000), history[1].Amount)
+}
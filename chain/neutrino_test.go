@@ -0,0 +1,62 @@
+package chain
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPkScript = []byte{0x00, 0x14, 0x01, 0x02, 0x03}
+
+// Test_ApplyBlockToUTXOSet_TracksSpends guards against ListUTXOs resurfacing
+// outputs that were later spent: a block creating an output followed by a
+// block spending it must leave the set empty, not report both as unspent.
+func Test_ApplyBlockToUTXOSet_TracksSpends(t *testing.T) {
+	unspent := make(map[wire.OutPoint]UTXO)
+
+	funding := wire.NewMsgTx(wire.TxVersion)
+	funding.AddTxOut(wire.NewTxOut(50_000, testPkScript))
+	applyBlockToUTXOSet(unspent, []*wire.MsgTx{funding}, testPkScript, 100)
+
+	assert.Len(t, unspent, 1)
+	fundingOutpoint := wire.OutPoint{Hash: funding.TxHash(), Index: 0}
+	assert.Equal(t, btcutil.Amount(50_000), unspent[fundingOutpoint].Amount)
+
+	spending := wire.NewMsgTx(wire.TxVersion)
+	spending.AddTxIn(wire.NewTxIn(&fundingOutpoint, nil, nil))
+	applyBlockToUTXOSet(unspent, []*wire.MsgTx{spending}, testPkScript, 101)
+
+	assert.Empty(t, unspent, "spent output must not be reported as unspent")
+}
+
+// Test_ApplyBlockToUTXOSet_IgnoresOtherScripts guards against outputs for a
+// different script polluting the set.
+func Test_ApplyBlockToUTXOSet_IgnoresOtherScripts(t *testing.T) {
+	unspent := make(map[wire.OutPoint]UTXO)
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxOut(wire.NewTxOut(10_000, []byte{0x00, 0x14, 0xff}))
+	applyBlockToUTXOSet(unspent, []*wire.MsgTx{tx}, testPkScript, 100)
+
+	assert.Empty(t, unspent)
+}
+
+// Test_ApplyBlockToUTXOSet_SpendWithinSameBlock covers an output created and
+// spent within the same block, which a naive per-transaction pass could
+// still report as unspent depending on iteration order.
+func Test_ApplyBlockToUTXOSet_SpendWithinSameBlock(t *testing.T) {
+	unspent := make(map[wire.OutPoint]UTXO)
+
+	funding := wire.NewMsgTx(wire.TxVersion)
+	funding.AddTxOut(wire.NewTxOut(50_000, testPkScript))
+	fundingOutpoint := wire.OutPoint{Hash: funding.TxHash(), Index: 0}
+
+	spending := wire.NewMsgTx(wire.TxVersion)
+	spending.AddTxIn(wire.NewTxIn(&fundingOutpoint, nil, nil))
+
+	applyBlockToUTXOSet(unspent, []*wire.MsgTx{funding, spending}, testPkScript, 100)
+
+	assert.Empty(t, unspent)
+}
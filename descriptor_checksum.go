@@ -0,0 +1,94 @@
+package segwit
+
+import (
+	"errors"
+	"strings"
+)
+
+// descriptorInputCharset is the set of characters permitted in a descriptor
+// string for the purposes of the BIP380 checksum, in the order BIP380
+// assigns them their 6-bit values.
+const descriptorInputCharset = "0123456789()[],'/*abcdefgh@:$%{}" +
+	"IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+
+// descriptorChecksumCharset is the 32-character alphabet BIP380 checksums
+// are encoded with.
+const descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var descriptorGenerator = [5]uint64{
+	0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd,
+}
+
+const ErrDescriptorChar = "invalid character in descriptor"
+
+// descriptorChecksum computes the 8-character BIP380 checksum for desc, the
+// same algorithm Bitcoin Core uses for `importdescriptors`.
+func descriptorChecksum(desc string) (string, error) {
+	symbols, err := descriptorExpand(desc)
+	if err != nil {
+		return "", err
+	}
+	symbols = append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	checksum := descriptorPolymod(symbols) ^ 1
+
+	out := make([]byte, 8)
+	for i := range out {
+		out[i] = descriptorChecksumCharset[(checksum>>(5*(7-uint(i))))&31]
+	}
+	return string(out), nil
+}
+
+// appendDescriptorChecksum returns desc with its BIP380 checksum appended as
+// `desc#checksum`.
+func appendDescriptorChecksum(desc string) (string, error) {
+	checksum, err := descriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+	return desc + "#" + checksum, nil
+}
+
+// descriptorExpand maps each descriptor character to its BIP380 symbol
+// stream, packing three 6-bit characters into four 5-bit symbols.
+func descriptorExpand(desc string) ([]uint64, error) {
+	var symbols []uint64
+	var groups []uint64
+
+	for _, c := range desc {
+		idx := strings.IndexRune(descriptorInputCharset, c)
+		if idx < 0 {
+			return nil, errors.New(ErrDescriptorChar)
+		}
+		v := uint64(idx)
+		symbols = append(symbols, v&31)
+		groups = append(groups, v>>5)
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = nil
+		}
+	}
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+	return symbols, nil
+}
+
+// descriptorPolymod runs the BIP380 checksum's Bech32-style polymod over
+// symbols.
+func descriptorPolymod(symbols []uint64) uint64 {
+	var chk uint64 = 1
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 != 0 {
+				chk ^= descriptorGenerator[i]
+			}
+		}
+	}
+	return chk
+}
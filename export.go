@@ -0,0 +1,303 @@
+package segwit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// ImportFormat selects the script dialect produced by ExportImportScript.
+type ImportFormat int
+
+const (
+	// FormatBitcoinCLI emits a sequence of `bitcoin-cli importprivkey` calls.
+	FormatBitcoinCLI ImportFormat = iota
+	// FormatBitcoinCLIWatchOnly emits `bitcoin-cli importaddress` calls for
+	// watch-only (no private key) recovery.
+	FormatBitcoinCLIWatchOnly
+	// FormatBitcoinImportWallet emits lines in the `bitcoind` `importwallet`
+	// dump format (`privkey timestamp label=... addr=...`).
+	FormatBitcoinImportWallet
+	// FormatDescriptor emits BIP380 output descriptors suitable for
+	// `bitcoin-cli importdescriptors`.
+	FormatDescriptor
+)
+
+const (
+	// DefaultRecoveryWindow is the number of addresses scanned per branch
+	// when no ExportOptions.RecoveryWindow is supplied.
+	DefaultRecoveryWindow = 2500
+
+	ErrUnsupportedFormat = "unsupported import format"
+	ErrExportPrivateKey  = "failed to export private key material"
+)
+
+// ExportOptions configures how ExportImportScript expands and annotates the
+// wallet's keys for the chosen ImportFormat.
+type ExportOptions struct {
+	// RecoveryWindow is the number of external/internal addresses to emit
+	// per derivation path. Defaults to DefaultRecoveryWindow.
+	RecoveryWindow uint32
+
+	// ExtraPaths are additional base derivation paths (e.g. other lnd-style
+	// accounts) to expand alongside the wallet's own path.
+	ExtraPaths []string
+
+	// RescanFrom is the block height to pass as the rescan/timestamp
+	// parameter. Zero means "rescan from genesis".
+	RescanFrom int32
+
+	// Label is attached to emitted entries where the target format supports
+	// one (importwallet, importprivkey, importaddress).
+	Label string
+}
+
+// ExportImportScript renders a deterministic script or descriptor set a user
+// can paste into bitcoin-cli (or another wallet's import flow) to recover
+// every address this Wallet can derive.
+func (s *Wallet) ExportImportScript(format ImportFormat, opts ExportOptions) (string, error) {
+	if opts.RecoveryWindow == 0 {
+		opts.RecoveryWindow = DefaultRecoveryWindow
+	}
+
+	paths := append([]string{s.path}, opts.ExtraPaths...)
+
+	switch format {
+	case FormatDescriptor:
+		return s.exportDescriptors(paths)
+	case FormatBitcoinImportWallet:
+		return s.exportImportWallet(paths, opts)
+	case FormatBitcoinCLI:
+		return s.exportBitcoinCLI(paths, opts, false)
+	case FormatBitcoinCLIWatchOnly:
+		return s.exportBitcoinCLI(paths, opts, true)
+	default:
+		return "", errors.New(ErrUnsupportedFormat)
+	}
+}
+
+// exportDescriptors produces one wpkh() receive descriptor and one change
+// descriptor per base path, each carrying its BIP32 derivation origin and
+// BIP380 checksum. Each path is expected to already reach branch depth (e.g.
+// the wallet's default m/84'/0'/0'/0), so the descriptor's xpub is derived
+// one level up, at the account, with the branch/index left as the
+// descriptor's own non-hardened suffix.
+func (s *Wallet) exportDescriptors(paths []string) (string, error) {
+	var lines []string
+	for _, path := range paths {
+		accountPath, err := parentPath(path)
+		if err != nil {
+			return "", err
+		}
+		account, err := s.deriveBasePath(accountPath)
+		if err != nil {
+			return "", err
+		}
+		xpub, err := account.ExtendedPublicKey()
+		if err != nil {
+			return "", err
+		}
+		origin, err := descriptorOrigin(accountPath)
+		if err != nil {
+			return "", err
+		}
+		// The origin path starts at the master key, so the origin names the
+		// master key's fingerprint rather than the account key's.
+		fp, err := (&Wallet{extendedKey: s.root}).fingerprint()
+		if err != nil {
+			return "", err
+		}
+		for _, branch := range []int{0, 1} {
+			desc := fmt.Sprintf("wpkh([%s%s]%s/%d/*)", fp, origin, xpub, branch)
+			withChecksum, err := appendDescriptorChecksum(desc)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, withChecksum)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// exportImportWallet iterates each path's external (branch 0) and internal
+// (branch 1) chains through the recovery window and emits one
+// `importwallet`-style dump line per key. Each path is expected to already
+// reach branch depth (e.g. the wallet's default m/84'/0'/0'/0), so the
+// account-level key is derived one level up, the same way exportDescriptors
+// does, and both branches are expanded from there.
+func (s *Wallet) exportImportWallet(paths []string, opts ExportOptions) (string, error) {
+	var lines []string
+	for _, path := range paths {
+		account, err := s.deriveAccount(path)
+		if err != nil {
+			return "", err
+		}
+		for _, branch := range []int{0, 1} {
+			branchKey, err := account.Derive(branch)
+			if err != nil {
+				return "", err
+			}
+			for idx := uint32(0); idx < opts.RecoveryWindow; idx++ {
+				child, err := branchKey.Derive(idx)
+				if err != nil {
+					return "", err
+				}
+				wif, err := child.PrivateKey()
+				if err != nil {
+					return "", fmt.Errorf("%s: %w", ErrExportPrivateKey, err)
+				}
+				lines = append(lines, fmt.Sprintf("%s %d label=%s addr=%s",
+					wif, opts.RescanFrom, importWalletLabel(opts.Label, branchKey.path, idx), child.AddressHex()))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// exportBitcoinCLI emits one `bitcoin-cli importprivkey`/`importaddress` call
+// per address across both branches of the recovery window; see
+// exportImportWallet.
+func (s *Wallet) exportBitcoinCLI(paths []string, opts ExportOptions, watchOnly bool) (string, error) {
+	var lines []string
+	for _, path := range paths {
+		account, err := s.deriveAccount(path)
+		if err != nil {
+			return "", err
+		}
+		for _, branch := range []int{0, 1} {
+			branchKey, err := account.Derive(branch)
+			if err != nil {
+				return "", err
+			}
+			for idx := uint32(0); idx < opts.RecoveryWindow; idx++ {
+				child, err := branchKey.Derive(idx)
+				if err != nil {
+					return "", err
+				}
+				label := importWalletLabel(opts.Label, branchKey.path, idx)
+				if watchOnly {
+					lines = append(lines, fmt.Sprintf(
+						"bitcoin-cli importaddress %q %q true", child.AddressHex(), label))
+					continue
+				}
+				wif, err := child.PrivateKey()
+				if err != nil {
+					return "", fmt.Errorf("%s: %w", ErrExportPrivateKey, err)
+				}
+				lines = append(lines, fmt.Sprintf(
+					"bitcoin-cli importprivkey %q %q false", wif, label))
+			}
+		}
+	}
+	if opts.RescanFrom != 0 {
+		lines = append(lines, fmt.Sprintf("bitcoin-cli rescanblockchain %d", opts.RescanFrom))
+	} else {
+		lines = append(lines, "bitcoin-cli rescanblockchain")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// deriveAccount returns the Wallet positioned at path's parent account level,
+// the same derivation depth exportDescriptors uses, so callers can expand
+// both the external (branch 0) and internal (branch 1) chains beneath it.
+func (s *Wallet) deriveAccount(path string) (*Wallet, error) {
+	accountPath, err := parentPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return s.deriveBasePath(accountPath)
+}
+
+// deriveBasePath returns the Wallet positioned at path, reusing the receiver
+// when it already matches.
+func (s *Wallet) deriveBasePath(path string) (*Wallet, error) {
+	if path == s.path {
+		return s, nil
+	}
+	key, err := DeriveKeyFromPath(s.root, path)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := deriveAddressFromPublicKey(key, s.params)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		mnemonic:    s.mnemonic,
+		path:        path,
+		root:        s.root,
+		extendedKey: key,
+		address:     addr,
+		params:      s.params,
+	}, nil
+}
+
+// Fingerprint returns the BIP32 fingerprint of the wallet's current extended
+// key: the first 4 bytes of the Hash160 of its public key. Descriptor and
+// PSBT key origins carry this value computed for the master key.
+func (s *Wallet) Fingerprint() ([4]byte, error) {
+	pubKey, err := s.extendedKey.ECPubKey()
+	if err != nil {
+		return [4]byte{}, err
+	}
+	hash := btcutil.Hash160(pubKey.SerializeCompressed())
+	var fp [4]byte
+	copy(fp[:], hash[:4])
+	return fp, nil
+}
+
+// fingerprint renders Fingerprint as the lowercase hex string used in
+// descriptor key origins.
+func (s *Wallet) fingerprint() (string, error) {
+	fp, err := s.Fingerprint()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", fp[:]), nil
+}
+
+// descriptorOrigin renders a derivation path such as `m/84'/0'/0'/0` as the
+// descriptor origin suffix `/84h/0h/0h/0` used after the fingerprint.
+func descriptorOrigin(path string) (string, error) {
+	trimmed := strings.TrimPrefix(path, "m")
+	segments := strings.Split(strings.Trim(trimmed, "/"), "/")
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		hardened := strings.HasSuffix(seg, "'") || strings.HasSuffix(seg, "h")
+		seg = strings.TrimSuffix(strings.TrimSuffix(seg, "'"), "h")
+		if _, err := strconv.ParseUint(seg, 10, 32); err != nil {
+			return "", fmt.Errorf("%s: %w", ErrInvalidPath, err)
+		}
+		b.WriteByte('/')
+		b.WriteString(seg)
+		if hardened {
+			b.WriteByte('h')
+		}
+	}
+	return b.String(), nil
+}
+
+// importWalletLabel derives a deterministic label for an exported key:
+// label/index when the caller supplied a label, path/index otherwise.
+func importWalletLabel(label, path string, index uint32) string {
+	if label != "" {
+		return fmt.Sprintf("%s/%d", label, index)
+	}
+	return fmt.Sprintf("%s/%d", path, index)
+}
+
+// parentPath strips the last derivation step from path, returning the
+// account-level path that a branch path like .../0 or .../1 descends from.
+func parentPath(path string) (string, error) {
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 {
+		return "", errors.New(ErrInvalidPath)
+	}
+	return path[:idx], nil
+}
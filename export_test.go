@@ -0,0 +1,69 @@
+package segwit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zeroMnemonic is the standard all-zero-entropy BIP39 test vector, used here
+// so the exported addresses below are reproducible.
+const zeroMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// Test_ExportImportScript_MatchesWalletAddress guards against exported
+// scripts covering a different branch/index than the wallet's own address,
+// and against branch iteration being dropped: with the default path, one
+// line must cover the external (branch 0) chain matching w.Derive(0), and
+// one the internal (branch 1) chain.
+func Test_ExportImportScript_MatchesWalletAddress(t *testing.T) {
+	w, err := New(&Config{Mnemonic: zeroMnemonic, Network: NetworkMainnet})
+	assert.NoError(t, err)
+
+	accountPath, err := parentPath(w.Path())
+	assert.NoError(t, err)
+	account, err := w.deriveBasePath(accountPath)
+	assert.NoError(t, err)
+	external, err := account.Derive(0)
+	assert.NoError(t, err)
+	receiveZero, err := external.Derive(0)
+	assert.NoError(t, err)
+	internal, err := account.Derive(1)
+	assert.NoError(t, err)
+	changeZero, err := internal.Derive(0)
+	assert.NoError(t, err)
+
+	script, err := w.ExportImportScript(FormatBitcoinImportWallet, ExportOptions{RecoveryWindow: 1})
+	assert.NoError(t, err)
+
+	lines := strings.Split(script, "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "addr="+receiveZero.AddressHex())
+	assert.Contains(t, lines[1], "addr="+changeZero.AddressHex())
+}
+
+// Test_ExportImportScript_Descriptor_UsesAccountLevelXpub guards against the
+// descriptor's xpub being derived one level too deep: it must be the
+// account-level key (one level above the wallet's branch path), with /0/*
+// and /1/* left as the descriptor's own suffix.
+func Test_ExportImportScript_Descriptor_UsesAccountLevelXpub(t *testing.T) {
+	w, err := New(&Config{Mnemonic: zeroMnemonic, Network: NetworkMainnet})
+	assert.NoError(t, err)
+
+	accountPath, err := parentPath(w.Path())
+	assert.NoError(t, err)
+	account, err := w.deriveBasePath(accountPath)
+	assert.NoError(t, err)
+	accountXpub, err := account.ExtendedPublicKey()
+	assert.NoError(t, err)
+
+	script, err := w.ExportImportScript(FormatDescriptor, ExportOptions{})
+	assert.NoError(t, err)
+
+	lines := strings.Split(script, "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], accountXpub)
+	assert.Contains(t, lines[0], "wpkh([")
+	assert.True(t, strings.Contains(lines[0], accountXpub+"/0/*"))
+	assert.True(t, strings.Contains(lines[1], accountXpub+"/1/*"))
+}
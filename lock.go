@@ -0,0 +1,178 @@
+package segwit
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	bip39 "github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	ErrWalletSealed    = "wallet has no seal configured; call Seal or reopen it via a store"
+	ErrInvalidPrivPass = "invalid private passphrase"
+)
+
+// sealParams records the scrypt parameters and salt used to derive the AEAD
+// key guarding a Wallet's private material while locked, so Unlock can
+// re-derive the same key from the passphrase alone.
+type sealParams struct {
+	salt    []byte
+	n, r, p int
+}
+
+// Seal arms the wallet for locking: it derives an AEAD key from privPass via
+// scrypt(n, r, p) over a freshly generated salt and remembers the
+// salt/parameters so a later Unlock can reproduce the same key. The salt is
+// returned so a caller that persists the wallet (see segwit/store) can store
+// it alongside n/r/p.
+func (s *Wallet) Seal(privPass []byte, n, r, p int) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(privPass, salt, n, r, p, 32)
+	if err != nil {
+		return nil, err
+	}
+	s.seal = &sealParams{salt: salt, n: n, r: r, p: p}
+	s.privKey = key
+	return salt, nil
+}
+
+// Locked reconstructs a Wallet in its locked state from previously sealed
+// material: the extended public key and address are restored so Address and
+// ExtendedPublicKey keep working, while encMnemonic/nonce stay ciphertext
+// until Unlock is called with the matching private passphrase. It exists for
+// callers, such as segwit/store, that rehydrate a wallet from disk without
+// ever holding the mnemonic in memory.
+func Locked(path string, network Network, xpub string, encMnemonic, nonce, salt []byte, n, r, p int) (*Wallet, error) {
+	params, err := selectNetworkParams(network)
+	if err != nil {
+		return nil, err
+	}
+
+	extendedKey, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := deriveAddressFromPublicKey(extendedKey, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		path:        path,
+		extendedKey: extendedKey,
+		address:     addr,
+		params:      params,
+		locked:      true,
+		seal:        &sealParams{salt: salt, n: n, r: r, p: p},
+		encMnemonic: encMnemonic,
+		encNonce:    nonce,
+	}, nil
+}
+
+// Lock encrypts the wallet's mnemonic in memory with the key established by
+// Seal, then discards the plaintext mnemonic and the master key so
+// PrivateKey fails and Derive can no longer produce hardened children until
+// Unlock. Address and
+// ExtendedPublicKey keep working off the retained, now-neutered extended key.
+func (s *Wallet) Lock() error {
+	if s.locked {
+		return nil
+	}
+	if s.seal == nil {
+		return errors.New(ErrWalletSealed)
+	}
+
+	nonce := make([]byte, 12)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	aead, err := newAEAD(s.privKey)
+	if err != nil {
+		return err
+	}
+
+	neutered, err := s.extendedKey.Neuter()
+	if err != nil {
+		return err
+	}
+
+	s.encMnemonic = aead.Seal(nil, nonce, []byte(s.mnemonic), nil)
+	s.encNonce = nonce
+	s.mnemonic = ""
+	s.root = nil
+	s.extendedKey = neutered
+	s.privKey = nil
+	s.locked = true
+	return nil
+}
+
+// Unlock reverses Lock: it re-derives the AEAD key from privPass and the
+// wallet's stored salt, decrypts the mnemonic, and re-derives the master and
+// extended keys so PrivateKey and hardened Derive work again.
+func (s *Wallet) Unlock(privPass []byte) error {
+	if !s.locked {
+		return nil
+	}
+	if s.seal == nil {
+		return errors.New(ErrWalletSealed)
+	}
+
+	key, err := scrypt.Key(privPass, s.seal.salt, s.seal.n, s.seal.r, s.seal.p, 32)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, s.encNonce, s.encMnemonic, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrInvalidPrivPass, err)
+	}
+
+	seed := bip39.NewSeed(string(plaintext), "")
+	masterKey, err := generateMasterKey(seed, s.params)
+	if err != nil {
+		return err
+	}
+	extendedKey, err := DeriveKeyFromPath(masterKey, s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mnemonic = string(plaintext)
+	s.root = masterKey
+	s.extendedKey = extendedKey
+	s.privKey = key
+	s.locked = false
+	return nil
+}
+
+// IsLocked reports whether the wallet's private material is currently
+// encrypted in memory.
+func (s *Wallet) IsLocked() bool {
+	return s.locked
+}
+
+// SealedMnemonic returns the ciphertext and nonce produced by the wallet's
+// last Lock call, so a caller that persists the wallet (see segwit/store)
+// can write them to disk alongside the salt returned by Seal.
+func (s *Wallet) SealedMnemonic() (ciphertext, nonce []byte) {
+	return s.encMnemonic, s.encNonce
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
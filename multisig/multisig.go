@@ -0,0 +1,159 @@
+// Package multisig adds P2WSH M-of-N multisig support on top of the segwit
+// module's single-signer P2WPKH wallet, organized around Series/account
+// abstractions inspired by btcwallet's votingpool.
+package multisig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+
+	segwit "github.com/ariden83/segwit.go"
+)
+
+const (
+	ErrSeriesExists   = "series already exists"
+	ErrSeriesNotFound = "series not found"
+	ErrUnsupportedNet = "unsupported network type: choose either 'mainnet' or 'testnet'"
+	ErrInvalidN       = "N must equal the number of Xpubs"
+)
+
+// SeriesConfig describes one M-of-N co-signer set: M signatures are required
+// out of the ordered extended public keys in Xpubs. N must equal len(Xpubs);
+// it is kept as an explicit field, rather than derived, so a caller who
+// expects e.g. 5 co-signers gets an error instead of a silently smaller
+// series if Xpubs is accidentally short.
+type SeriesConfig struct {
+	M, N  int
+	Xpubs []string
+
+	// OwnMnemonic optionally identifies a co-signer key this process
+	// actually controls, so the series can sign for it via
+	// MultisigWallet.OwnPrivateKey instead of only deriving public
+	// addresses and redeem scripts. OwnPath is the derivation path used to
+	// reach the account-level extended key whose xpub appears in Xpubs
+	// (e.g. the same path passed to segwit.Config.Path). When OwnMnemonic
+	// is set, the resulting extended public key must match one entry in
+	// Xpubs.
+	OwnMnemonic string
+	OwnPath     string
+}
+
+// MultisigConfig carries the parameters needed to assemble a MultisigWallet:
+// the network the addresses belong to and the initial set of series, keyed
+// by an arbitrary series ID the caller chooses.
+type MultisigConfig struct {
+	Network segwit.Network
+	Series  map[uint32]SeriesConfig
+}
+
+// MultisigWallet owns one or more Series and derives P2WSH addresses and
+// redeem scripts from them.
+type MultisigWallet struct {
+	params *chaincfg.Params
+	series map[uint32]*Series
+}
+
+// New assembles a MultisigWallet from cfg, validating and parsing every
+// series' co-signer extended public keys up front.
+func New(cfg *MultisigConfig) (*MultisigWallet, error) {
+	params, err := networkParams(cfg.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := &MultisigWallet{
+		params: params,
+		series: make(map[uint32]*Series, len(cfg.Series)),
+	}
+	for id, seriesCfg := range cfg.Series {
+		series, err := newSeries(seriesCfg, params)
+		if err != nil {
+			return nil, fmt.Errorf("series %d: %w", id, err)
+		}
+		wallet.series[id] = series
+	}
+	return wallet, nil
+}
+
+// AddSeries registers a new series under seriesID. Use a new seriesID for
+// every upgrade (e.g. adding a co-signer) rather than mutating an existing
+// series in place, so addresses already derived under the old seriesID keep
+// resolving to the same redeem script; ReplaceKey is for deliberate key
+// rotation within a single series instead.
+func (w *MultisigWallet) AddSeries(seriesID uint32, cfg SeriesConfig) error {
+	if _, ok := w.series[seriesID]; ok {
+		return errors.New(ErrSeriesExists)
+	}
+	series, err := newSeries(cfg, w.params)
+	if err != nil {
+		return err
+	}
+	w.series[seriesID] = series
+	return nil
+}
+
+// DeriveAddress derives the P2WSH address for seriesID at the given branch
+// (0 external, 1 internal by convention) and child index.
+func (w *MultisigWallet) DeriveAddress(seriesID, branch, index uint32) (*btcutil.AddressWitnessScriptHash, error) {
+	series, ok := w.series[seriesID]
+	if !ok {
+		return nil, errors.New(ErrSeriesNotFound)
+	}
+	hash, err := series.scriptHash(branch, index)
+	if err != nil {
+		return nil, err
+	}
+	return btcutil.NewAddressWitnessScriptHash(hash[:], w.params)
+}
+
+// WitnessScript returns the raw BIP67-sorted M-of-N redeem script for
+// seriesID at the given branch and child index, for use when spending.
+func (w *MultisigWallet) WitnessScript(seriesID, branch, index uint32) ([]byte, error) {
+	series, ok := w.series[seriesID]
+	if !ok {
+		return nil, errors.New(ErrSeriesNotFound)
+	}
+	return series.witnessScript(branch, index)
+}
+
+// OwnPrivateKey returns the private key for the co-signer identified by
+// seriesID's SeriesConfig.OwnMnemonic, derived to the given branch and
+// index, so the caller can produce its own signature share for a
+// MultisigWallet-controlled redeem script. It fails if the series was
+// created without an OwnMnemonic.
+func (w *MultisigWallet) OwnPrivateKey(seriesID, branch, index uint32) (*btcec.PrivateKey, error) {
+	series, ok := w.series[seriesID]
+	if !ok {
+		return nil, errors.New(ErrSeriesNotFound)
+	}
+	return series.ownPrivateKey(branch, index)
+}
+
+// ReplaceKey rotates oldXpub for newXpub within seriesID in place. Because
+// this mutates the series rather than creating a new one, every address
+// derived under seriesID after this call uses the rotated key; addresses
+// already handed out under seriesID before rotation will no longer match
+// future derivations and must be tracked separately by the caller.
+func (w *MultisigWallet) ReplaceKey(seriesID uint32, oldXpub, newXpub string) error {
+	series, ok := w.series[seriesID]
+	if !ok {
+		return errors.New(ErrSeriesNotFound)
+	}
+	return series.replaceKey(oldXpub, newXpub)
+}
+
+// networkParams maps a segwit.Network to its btcd chain parameters.
+func networkParams(network segwit.Network) (*chaincfg.Params, error) {
+	switch network {
+	case segwit.NetworkMainnet:
+		return &chaincfg.MainNetParams, nil
+	case segwit.NetworkTestnet:
+		return &chaincfg.TestNet3Params, nil
+	default:
+		return nil, errors.New(ErrUnsupportedNet)
+	}
+}
@@ -0,0 +1,187 @@
+package multisig
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/tyler-smith/go-bip39"
+
+	segwit "github.com/ariden83/segwit.go"
+)
+
+// testXpubWithMnemonic returns a fresh mnemonic and the extended public key
+// it derives to at path, for tests exercising SeriesConfig.OwnMnemonic.
+func testXpubWithMnemonic(t *testing.T, path string) (mnemonic, xpub string) {
+	entropy, err := bip39.NewEntropy(128)
+	assert.NoError(t, err)
+	mnemonic, err = bip39.NewMnemonic(entropy)
+	assert.NoError(t, err)
+
+	seed := bip39.NewSeed(mnemonic, "")
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	key, err := segwit.DeriveKeyFromPath(master, path)
+	assert.NoError(t, err)
+	pub, err := key.Neuter()
+	assert.NoError(t, err)
+	return mnemonic, pub.String()
+}
+
+func testXpub(t *testing.T) string {
+	entropy, err := bip39.NewEntropy(128)
+	assert.NoError(t, err)
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	assert.NoError(t, err)
+
+	seed := bip39.NewSeed(mnemonic, "")
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+
+	xpub, err := master.Neuter()
+	assert.NoError(t, err)
+	return xpub.String()
+}
+
+func testConfig(t *testing.T, m, n int) *MultisigConfig {
+	xpubs := make([]string, n)
+	for i := range xpubs {
+		xpubs[i] = testXpub(t)
+	}
+	return &MultisigConfig{
+		Network: segwit.NetworkMainnet,
+		Series: map[uint32]SeriesConfig{
+			0: {M: m, N: n, Xpubs: xpubs},
+		},
+	}
+}
+
+func Test_New(t *testing.T) {
+	wallet, err := New(testConfig(t, 2, 3))
+	assert.NoError(t, err)
+	assert.NotNil(t, wallet)
+
+	_, err = New(&MultisigConfig{
+		Network: segwit.NetworkMainnet,
+		Series: map[uint32]SeriesConfig{
+			0: {M: 4, N: 3, Xpubs: []string{testXpub(t), testXpub(t), testXpub(t)}},
+		},
+	})
+	assert.EqualError(t, err, "series 0: "+ErrInvalidM)
+}
+
+// Test_New_RejectsNMismatch guards against a caller-specified N silently
+// being ignored in favor of len(Xpubs): a SeriesConfig claiming N=5 with
+// only 3 Xpubs must fail instead of producing a silent 3-of-3 series.
+func Test_New_RejectsNMismatch(t *testing.T) {
+	_, err := New(&MultisigConfig{
+		Network: segwit.NetworkMainnet,
+		Series: map[uint32]SeriesConfig{
+			0: {M: 3, N: 5, Xpubs: []string{testXpub(t), testXpub(t), testXpub(t)}},
+		},
+	})
+	assert.EqualError(t, err, "series 0: "+ErrInvalidN)
+}
+
+func Test_DeriveAddress_Deterministic(t *testing.T) {
+	wallet, err := New(testConfig(t, 2, 3))
+	assert.NoError(t, err)
+
+	addr1, err := wallet.DeriveAddress(0, 0, 0)
+	assert.NoError(t, err)
+	addr2, err := wallet.DeriveAddress(0, 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, addr1.EncodeAddress(), addr2.EncodeAddress(), "deriving the same branch/index twice must be deterministic")
+
+	addrOther, err := wallet.DeriveAddress(0, 0, 1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, addr1.EncodeAddress(), addrOther.EncodeAddress())
+}
+
+func Test_DeriveAddress_UnknownSeries(t *testing.T) {
+	wallet, err := New(testConfig(t, 2, 3))
+	assert.NoError(t, err)
+
+	_, err = wallet.DeriveAddress(99, 0, 0)
+	assert.EqualError(t, err, ErrSeriesNotFound)
+}
+
+func Test_ReplaceKey(t *testing.T) {
+	wallet, err := New(testConfig(t, 2, 3))
+	assert.NoError(t, err)
+
+	before, err := wallet.DeriveAddress(0, 0, 0)
+	assert.NoError(t, err)
+
+	oldXpub := wallet.series[0].xpubs[0].String()
+	newXpub := testXpub(t)
+	err = wallet.ReplaceKey(0, oldXpub, newXpub)
+	assert.NoError(t, err)
+
+	after, err := wallet.DeriveAddress(0, 0, 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, before.EncodeAddress(), after.EncodeAddress(), "rotating a key must change future derivations")
+
+	err = wallet.ReplaceKey(0, oldXpub, newXpub)
+	assert.EqualError(t, err, ErrKeyNotInSeries)
+}
+
+// Test_OwnPrivateKey guards against SeriesConfig.OwnMnemonic being accepted
+// but never actually usable to sign: the derived private key's public half
+// must appear among the series' own sorted pubkeys at the same branch/index.
+func Test_OwnPrivateKey(t *testing.T) {
+	const ownPath = "m/48'/0'/0'"
+	mnemonic, ownXpub := testXpubWithMnemonic(t, ownPath)
+	xpubs := []string{ownXpub, testXpub(t), testXpub(t)}
+
+	wallet, err := New(&MultisigConfig{
+		Network: segwit.NetworkMainnet,
+		Series: map[uint32]SeriesConfig{
+			0: {M: 2, N: 3, Xpubs: xpubs, OwnMnemonic: mnemonic, OwnPath: ownPath},
+		},
+	})
+	assert.NoError(t, err)
+
+	privKey, err := wallet.OwnPrivateKey(0, 0, 0)
+	assert.NoError(t, err)
+
+	pubKeys, err := wallet.series[0].sortedPubKeys(0, 0)
+	assert.NoError(t, err)
+	ownPub := privKey.PubKey().SerializeCompressed()
+	found := false
+	for _, pk := range pubKeys {
+		if bytes.Equal(pk, ownPub) {
+			found = true
+		}
+	}
+	assert.True(t, found, "own private key's pubkey must appear among the series' sorted pubkeys")
+}
+
+// Test_OwnPrivateKey_NoOwnMnemonic guards against a watch-only series
+// (created without OwnMnemonic) silently returning a zero-value key instead
+// of a clear error.
+func Test_OwnPrivateKey_NoOwnMnemonic(t *testing.T) {
+	wallet, err := New(testConfig(t, 2, 3))
+	assert.NoError(t, err)
+
+	_, err = wallet.OwnPrivateKey(0, 0, 0)
+	assert.EqualError(t, err, ErrNoOwnKey)
+}
+
+// Test_New_OwnMnemonicNotInSeries guards against a caller passing an
+// OwnMnemonic that doesn't actually correspond to any of the series' Xpubs,
+// which would otherwise be silently ignored.
+func Test_New_OwnMnemonicNotInSeries(t *testing.T) {
+	const ownPath = "m/48'/0'/0'"
+	mnemonic, _ := testXpubWithMnemonic(t, ownPath)
+
+	_, err := New(&MultisigConfig{
+		Network: segwit.NetworkMainnet,
+		Series: map[uint32]SeriesConfig{
+			0: {M: 2, N: 3, Xpubs: []string{testXpub(t), testXpub(t), testXpub(t)}, OwnMnemonic: mnemonic, OwnPath: ownPath},
+		},
+	})
+	assert.EqualError(t, err, "series 0: "+ErrOwnKeyNotFound)
+}
@@ -0,0 +1,178 @@
+package multisig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	bip39 "github.com/tyler-smith/go-bip39"
+
+	segwit "github.com/ariden83/segwit.go"
+)
+
+const (
+	ErrInvalidM       = "M must be between 1 and N"
+	ErrInvalidXpub    = "invalid extended public key"
+	ErrKeyNotInSeries = "old extended public key not found in series"
+	ErrOwnKeyNotFound = "own mnemonic's extended public key does not match any xpub in the series"
+	ErrNoOwnKey       = "series has no signing key bound; set SeriesConfig.OwnMnemonic when creating it"
+)
+
+// Series holds an ordered set of co-signer extended public keys that make up
+// one M-of-N redeem script family, mirroring votingpool's Series.
+type Series struct {
+	m, n  int
+	xpubs []*hdkeychain.ExtendedKey
+
+	// ownKey is the private extended key matching one entry of xpubs, set
+	// when SeriesConfig.OwnMnemonic identifies a co-signer this process
+	// controls. Nil if the series was created watch-only.
+	ownKey *hdkeychain.ExtendedKey
+}
+
+// newSeries parses and validates a SeriesConfig into a Series.
+func newSeries(cfg SeriesConfig, params *chaincfg.Params) (*Series, error) {
+	if cfg.N != len(cfg.Xpubs) {
+		return nil, errors.New(ErrInvalidN)
+	}
+	if cfg.M < 1 || cfg.M > len(cfg.Xpubs) {
+		return nil, errors.New(ErrInvalidM)
+	}
+
+	xpubs := make([]*hdkeychain.ExtendedKey, len(cfg.Xpubs))
+	for i, raw := range cfg.Xpubs {
+		key, err := hdkeychain.NewKeyFromString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrInvalidXpub, err)
+		}
+		xpubs[i] = key
+	}
+
+	series := &Series{m: cfg.M, n: len(xpubs), xpubs: xpubs}
+	if cfg.OwnMnemonic != "" {
+		ownKey, err := ownKeyFromMnemonic(cfg.OwnMnemonic, cfg.OwnPath, params)
+		if err != nil {
+			return nil, err
+		}
+		ownXpub, err := ownKey.Neuter()
+		if err != nil {
+			return nil, err
+		}
+		if !containsXpub(xpubs, ownXpub.String()) {
+			return nil, errors.New(ErrOwnKeyNotFound)
+		}
+		series.ownKey = ownKey
+	}
+	return series, nil
+}
+
+// ownKeyFromMnemonic derives the account-level extended key at path from
+// mnemonic, the same way segwit.New derives a Wallet's own extendedKey.
+func ownKeyFromMnemonic(mnemonic, path string, params *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+	seed := bip39.NewSeed(mnemonic, "")
+	masterKey, err := hdkeychain.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	return segwit.DeriveKeyFromPath(masterKey, path)
+}
+
+// containsXpub reports whether xpub matches the string form of one of keys.
+func containsXpub(keys []*hdkeychain.ExtendedKey, xpub string) bool {
+	for _, key := range keys {
+		if key.String() == xpub {
+			return true
+		}
+	}
+	return false
+}
+
+// ownPrivateKey derives the private key for the series' bound own key at
+// branch/index, mirroring sortedPubKeys' derivation of the public keys.
+func (s *Series) ownPrivateKey(branch, index uint32) (*btcec.PrivateKey, error) {
+	if s.ownKey == nil {
+		return nil, errors.New(ErrNoOwnKey)
+	}
+	branchKey, err := s.ownKey.Derive(branch)
+	if err != nil {
+		return nil, err
+	}
+	childKey, err := branchKey.Derive(index)
+	if err != nil {
+		return nil, err
+	}
+	return childKey.ECPrivKey()
+}
+
+// replaceKey swaps oldXpub for newXpub in place, for key rotation.
+func (s *Series) replaceKey(oldXpub, newXpub string) error {
+	newKey, err := hdkeychain.NewKeyFromString(newXpub)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrInvalidXpub, err)
+	}
+
+	for i, key := range s.xpubs {
+		if key.String() == oldXpub {
+			s.xpubs[i] = newKey
+			return nil
+		}
+	}
+	return errors.New(ErrKeyNotInSeries)
+}
+
+// sortedPubKeys derives each co-signer's compressed pubkey at branch/index
+// and returns them in BIP67 sorted order.
+func (s *Series) sortedPubKeys(branch, index uint32) ([][]byte, error) {
+	pubKeys := make([][]byte, len(s.xpubs))
+	for i, xpub := range s.xpubs {
+		branchKey, err := xpub.Derive(branch)
+		if err != nil {
+			return nil, err
+		}
+		childKey, err := branchKey.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := childKey.ECPubKey()
+		if err != nil {
+			return nil, err
+		}
+		pubKeys[i] = pubKey.SerializeCompressed()
+	}
+
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i], pubKeys[j]) < 0
+	})
+	return pubKeys, nil
+}
+
+// witnessScript assembles the BIP67-sorted M-of-N redeem script for branch/index.
+func (s *Series) witnessScript(branch, index uint32) ([]byte, error) {
+	pubKeys, err := s.sortedPubKeys(branch, index)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := txscript.NewScriptBuilder().AddInt64(int64(s.m))
+	for _, pubKey := range pubKeys {
+		builder.AddData(pubKey)
+	}
+	builder.AddInt64(int64(s.n)).AddOp(txscript.OP_CHECKMULTISIG)
+	return builder.Script()
+}
+
+// scriptHash returns the SHA-256 digest of the witness script, as used by
+// P2WSH addresses.
+func (s *Series) scriptHash(branch, index uint32) ([32]byte, error) {
+	script, err := s.witnessScript(branch, index)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(script), nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ethereum/go-ethereum/accounts"
 	bip39 "github.com/tyler-smith/go-bip39"
+
+	"github.com/ariden83/segwit.go/chain"
 )
 
 // Network represents the type of blockchain network the wallet operates on.
@@ -45,6 +47,16 @@ type Wallet struct {
 	extendedKey *hdkeychain.ExtendedKey
 	address     *btcutil.AddressWitnessPubKeyHash
 	params      *chaincfg.Params
+
+	// locked and the fields below back Lock/Unlock; see lock.go.
+	locked      bool
+	seal        *sealParams
+	privKey     []byte
+	encMnemonic []byte
+	encNonce    []byte
+
+	// backend is the optional chain data source bound via Bind; see bind.go.
+	backend chain.Backend
 }
 
 // New creates a new Wallet from a configuration.
@@ -71,7 +83,7 @@ func New(config *Config) (*Wallet, error) {
 		return nil, err
 	}
 
-	key, err := deriveKeyFromPath(masterKey, config.Path)
+	key, err := DeriveKeyFromPath(masterKey, config.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -123,8 +135,8 @@ func generateMasterKey(seed []byte, params *chaincfg.Params) (*hdkeychain.Extend
 	return hdkeychain.NewMaster(seed, params)
 }
 
-// deriveKeyFromPath derives a key from the specified derivation path.
-func deriveKeyFromPath(masterKey *hdkeychain.ExtendedKey, path string) (*hdkeychain.ExtendedKey, error) {
+// DeriveKeyFromPath derives a key from the specified derivation path.
+func DeriveKeyFromPath(masterKey *hdkeychain.ExtendedKey, path string) (*hdkeychain.ExtendedKey, error) {
 	dpath, err := accounts.ParseDerivationPath(path)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", ErrInvalidPath, err)
@@ -153,13 +165,20 @@ func deriveAddressFromPublicKey(key *hdkeychain.ExtendedKey, params *chaincfg.Pa
 // convertToUint32 converts different index types to uint32.
 func convertToUint32(index interface{}) (uint32, error) {
 	switch v := index.(type) {
-	case int, int64:
-		if v.(int) < 0 {
+	case int:
+		if v < 0 {
+			return 0, errors.New(ErrIndexNegative)
+		}
+		return uint32(v), nil
+	case int64:
+		if v < 0 {
 			return 0, errors.New(ErrIndexNegative)
 		}
-		return uint32(v.(int)), nil
-	case uint, uint32:
-		return v.(uint32), nil
+		return uint32(v), nil
+	case uint:
+		return uint32(v), nil
+	case uint32:
+		return v, nil
 	default:
 		return 0, errors.New(ErrUnsupportedIndex)
 	}
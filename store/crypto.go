@@ -0,0 +1,100 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	segwit "github.com/ariden83/segwit.go"
+	"golang.org/x/crypto/scrypt"
+)
+
+const ErrInvalidMetadata = "corrupt or tampered wallet metadata"
+
+// deriveKey derives a 32-byte AEAD key from passphrase via scrypt. If salt is
+// nil, a fresh 16-byte salt is generated and returned alongside the key.
+func deriveKey(passphrase, salt []byte, opts *ScryptOptions) ([]byte, []byte, error) {
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, err
+		}
+	}
+	key, err := scrypt.Key(passphrase, salt, opts.N, opts.R, opts.P, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	return salt, key, nil
+}
+
+// seal AEAD-encrypts plaintext under key with a fresh nonce.
+func seal(key, plaintext []byte) (ciphertext, nonce []byte, err error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, nil), nonce, nil
+}
+
+// open AEAD-decrypts ciphertext under key and nonce.
+func open(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encodeMeta packs the watch-only fields persisted under the public
+// passphrase into a single pipe-delimited record. The derivation path is
+// not included here: it lives in its own derivationBucket, read back
+// without needing either passphrase, since it is never sensitive.
+func encodeMeta(xpub, addrHex string, network segwit.Network) string {
+	return strings.Join([]string{xpub, addrHex, string(network)}, "|")
+}
+
+// decodeMeta reverses encodeMeta.
+func decodeMeta(record string) (xpub, addrHex string, network segwit.Network, err error) {
+	parts := strings.Split(record, "|")
+	if len(parts) != 3 {
+		return "", "", "", errors.New(ErrInvalidMetadata)
+	}
+	return parts[0], parts[1], segwit.Network(parts[2]), nil
+}
+
+// encodeScryptOptions packs N/R/P as three big-endian uint32s.
+func encodeScryptOptions(opts *ScryptOptions) []byte {
+	buf := make([]byte, 12)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(opts.N))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(opts.R))
+	binary.BigEndian.PutUint32(buf[8:12], uint32(opts.P))
+	return buf
+}
+
+// decodeScryptOptions reverses encodeScryptOptions, falling back to
+// DefaultScryptOptions for malformed input.
+func decodeScryptOptions(buf []byte) *ScryptOptions {
+	if len(buf) != 12 {
+		return DefaultScryptOptions
+	}
+	return &ScryptOptions{
+		N: int(binary.BigEndian.Uint32(buf[0:4])),
+		R: int(binary.BigEndian.Uint32(buf[4:8])),
+		P: int(binary.BigEndian.Uint32(buf[8:12])),
+	}
+}
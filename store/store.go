@@ -0,0 +1,201 @@
+// Package store persists a segwit.Wallet to disk without ever keeping its
+// mnemonic in plaintext outside of an unlocked, in-memory Wallet. It follows
+// the waddrmgr convention of separate public and private passphrases: the
+// public passphrase guards metadata needed to watch the wallet (its address
+// and extended public key), while the private passphrase guards the seed
+// itself.
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	segwit "github.com/ariden83/segwit.go"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	ErrStoreExists      = "wallet store already exists at the given path"
+	ErrStoreNotFound    = "wallet store not found at the given path"
+	ErrWrongPassphrase  = "public or private passphrase is incorrect"
+	ErrUnsupportedStore = "wallet store version is not supported"
+)
+
+const storeVersion byte = 1
+
+var (
+	metaBucket       = []byte("meta")
+	derivationBucket = []byte("derivation")
+	cryptoBucket     = []byte("crypto")
+)
+
+// ScryptOptions configures the cost parameters used to derive the public and
+// private encryption keys that guard a store's contents. The defaults mirror
+// waddrmgr's.
+type ScryptOptions struct {
+	N, R, P int
+}
+
+// DefaultScryptOptions is used by Create when no ScryptOptions is given.
+var DefaultScryptOptions = &ScryptOptions{N: 32768, R: 8, P: 1}
+
+// Create derives a new Wallet from cfg, encrypts it at rest under pubPass and
+// privPass, and writes it to a bbolt file at path. It fails if the file at
+// path already holds a wallet store.
+func Create(path string, cfg *segwit.Config, pubPass, privPass []byte, scryptOpts *ScryptOptions) error {
+	if scryptOpts == nil {
+		scryptOpts = DefaultScryptOptions
+	}
+
+	wallet, err := segwit.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	pubSalt, pubKey, err := deriveKey(pubPass, nil, scryptOpts)
+	if err != nil {
+		return err
+	}
+
+	privSalt, err := wallet.Seal(privPass, scryptOpts.N, scryptOpts.R, scryptOpts.P)
+	if err != nil {
+		return err
+	}
+	if err := wallet.Lock(); err != nil {
+		return err
+	}
+
+	xpub, err := wallet.ExtendedPublicKey()
+	if err != nil {
+		return err
+	}
+
+	encMeta, metaNonce, err := seal(pubKey, []byte(encodeMeta(xpub, wallet.AddressHex(), cfg.Network)))
+	if err != nil {
+		return err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{NoGrowSync: false})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(metaBucket) != nil {
+			return errors.New(ErrStoreExists)
+		}
+
+		meta, err := tx.CreateBucket(metaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put([]byte("version"), []byte{storeVersion}); err != nil {
+			return err
+		}
+		if err := meta.Put([]byte("salt"), pubSalt); err != nil {
+			return err
+		}
+		if err := meta.Put([]byte("nonce"), metaNonce); err != nil {
+			return err
+		}
+		if err := meta.Put([]byte("data"), encMeta); err != nil {
+			return err
+		}
+
+		deriv, err := tx.CreateBucket(derivationBucket)
+		if err != nil {
+			return err
+		}
+		if err := deriv.Put([]byte("path"), []byte(wallet.Path())); err != nil {
+			return err
+		}
+
+		crypt, err := tx.CreateBucket(cryptoBucket)
+		if err != nil {
+			return err
+		}
+		encMnemonic, encNonce := wallet.SealedMnemonic()
+		if err := crypt.Put([]byte("privSalt"), privSalt); err != nil {
+			return err
+		}
+		if err := crypt.Put([]byte("encMnemonic"), encMnemonic); err != nil {
+			return err
+		}
+		if err := crypt.Put([]byte("encNonce"), encNonce); err != nil {
+			return err
+		}
+		return crypt.Put([]byte("scrypt"), encodeScryptOptions(scryptOpts))
+	})
+}
+
+// Open decrypts the store at path using pubPass and privPass and returns a
+// ready-to-use, unlocked Wallet. Its seal is retained, so the caller can
+// Lock/Unlock it again in memory without reopening the store.
+func Open(path string, pubPass, privPass []byte) (*segwit.Wallet, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrStoreNotFound, err)
+	}
+	defer db.Close()
+
+	var (
+		xpub, addrHex, derivationPath string
+		network                       segwit.Network
+		privSalt, encMnemonic         []byte
+		encNonce                      []byte
+		scryptOpts                    *ScryptOptions
+	)
+
+	err = db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		deriv := tx.Bucket(derivationBucket)
+		crypt := tx.Bucket(cryptoBucket)
+		if meta == nil || deriv == nil || crypt == nil {
+			return errors.New(ErrStoreNotFound)
+		}
+		if v := meta.Get([]byte("version")); len(v) != 1 || v[0] != storeVersion {
+			return errors.New(ErrUnsupportedStore)
+		}
+
+		pubSalt := append([]byte(nil), meta.Get([]byte("salt"))...)
+		metaNonce := append([]byte(nil), meta.Get([]byte("nonce"))...)
+		encMeta := append([]byte(nil), meta.Get([]byte("data"))...)
+		derivationPath = string(deriv.Get([]byte("path")))
+
+		privSalt = append([]byte(nil), crypt.Get([]byte("privSalt"))...)
+		encMnemonic = append([]byte(nil), crypt.Get([]byte("encMnemonic"))...)
+		encNonce = append([]byte(nil), crypt.Get([]byte("encNonce"))...)
+		scryptOpts = decodeScryptOptions(crypt.Get([]byte("scrypt")))
+
+		_, pubKey, err := deriveKey(pubPass, pubSalt, scryptOpts)
+		if err != nil {
+			return err
+		}
+		plaintext, err := open(pubKey, metaNonce, encMeta)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ErrWrongPassphrase, err)
+		}
+		xpub, addrHex, network, err = decodeMeta(string(plaintext))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := segwit.Locked(derivationPath, network, xpub, encMnemonic, encNonce, privSalt,
+		scryptOpts.N, scryptOpts.R, scryptOpts.P)
+	if err != nil {
+		return nil, err
+	}
+	if wallet.AddressHex() != addrHex {
+		return nil, errors.New(ErrInvalidMetadata)
+	}
+	if err := wallet.Unlock(privPass); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrWrongPassphrase, err)
+	}
+	return wallet, nil
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	segwit "github.com/ariden83/segwit.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/tyler-smith/go-bip39"
+)
+
+func testMnemonic(t *testing.T) string {
+	entropy, err := bip39.NewEntropy(128)
+	assert.NoError(t, err)
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	assert.NoError(t, err)
+	return mnemonic
+}
+
+func Test_CreateAndOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.db")
+	cfg := &segwit.Config{
+		Mnemonic: testMnemonic(t),
+		Network:  segwit.NetworkMainnet,
+	}
+	pubPass := []byte("pub-pass")
+	privPass := []byte("priv-pass")
+
+	err := Create(path, cfg, pubPass, privPass, &ScryptOptions{N: 16, R: 1, P: 1})
+	assert.NoError(t, err, "Failed to create store")
+
+	wallet, err := Open(path, pubPass, privPass)
+	assert.NoError(t, err, "Failed to open store")
+	assert.NotNil(t, wallet)
+	assert.False(t, wallet.IsLocked(), "Wallet returned by Open should be unlocked")
+
+	privKey, err := wallet.PrivateKey()
+	assert.NoError(t, err, "Unlocked wallet should expose its private key")
+	assert.NotEmpty(t, privKey)
+}
+
+// Test_Open_ReadsDerivationPathFromDerivationBucket guards against Open
+// silently falling back to the wallet's default path: with a non-default
+// Config.Path, the wallet Open returns must still derive the same address
+// Create saw, which is only possible if the path actually came from
+// derivationBucket rather than an assumed default.
+func Test_Open_ReadsDerivationPathFromDerivationBucket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.db")
+	cfg := &segwit.Config{
+		Mnemonic: testMnemonic(t),
+		Path:     `m/84'/0'/0'/1`,
+		Network:  segwit.NetworkMainnet,
+	}
+	pubPass := []byte("pub-pass")
+	privPass := []byte("priv-pass")
+
+	err := Create(path, cfg, pubPass, privPass, &ScryptOptions{N: 16, R: 1, P: 1})
+	assert.NoError(t, err)
+
+	wallet, err := Open(path, pubPass, privPass)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Path, wallet.Path())
+}
+
+func Test_Open_WrongPrivPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.db")
+	cfg := &segwit.Config{
+		Mnemonic: testMnemonic(t),
+		Network:  segwit.NetworkMainnet,
+	}
+	pubPass := []byte("pub-pass")
+
+	err := Create(path, cfg, pubPass, []byte("priv-pass"), &ScryptOptions{N: 16, R: 1, P: 1})
+	assert.NoError(t, err)
+
+	_, err = Open(path, pubPass, []byte("wrong-pass"))
+	assert.Error(t, err, "Opening with the wrong private passphrase should fail")
+}
+
+func Test_Open_WrongPubPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wallet.db")
+	cfg := &segwit.Config{
+		Mnemonic: testMnemonic(t),
+		Network:  segwit.NetworkMainnet,
+	}
+
+	err := Create(path, cfg, []byte("pub-pass"), []byte("priv-pass"), &ScryptOptions{N: 16, R: 1, P: 1})
+	assert.NoError(t, err)
+
+	_, err = Open(path, []byte("wrong-pub-pass"), []byte("priv-pass"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,311 @@
+// Package tx adds a PSBT-based transaction builder and signer on top of the
+// segwit module's P2WPKH wallet, so callers can actually spend the coins
+// they hold instead of only generating addresses.
+package tx
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/ethereum/go-ethereum/accounts"
+)
+
+const (
+	ErrNoUTXOs           = "no UTXOs provided"
+	ErrNoRecipients      = "no recipients provided"
+	ErrInsufficientFunds = "insufficient funds to cover outputs and fee"
+	ErrInvalidFeeRate    = "fee rate must be positive"
+	ErrMissingChangeInfo = "change address and derivation index are required when change is due"
+)
+
+// UTXO is a spendable output belonging to the wallet, identified by its
+// outpoint and annotated with the child index (relative to the wallet's
+// base path) that derives the key able to spend it.
+type UTXO struct {
+	Outpoint   wire.OutPoint
+	Amount     btcutil.Amount
+	PkScript   []byte
+	ChildIndex uint32
+}
+
+// Recipient is a single payment output.
+type Recipient struct {
+	Address btcutil.Address
+	Amount  btcutil.Amount
+}
+
+// ChangeOptions configures where leftover funds are returned to.
+type ChangeOptions struct {
+	Address btcutil.Address
+	// Branch is the derivation branch (conventionally 1, internal) the
+	// change address was derived from.
+	Branch uint32
+	Index  uint32
+}
+
+// Builder selects coins and assembles a BIP174 PSBT for the wallet's
+// P2WPKH UTXOs.
+type Builder struct {
+	// MasterFingerprint is embedded in each input's BIP32 derivation hint
+	// so hardware wallets and other PSBT consumers know which key to use.
+	MasterFingerprint [4]byte
+	// AccountPath is the wallet's account-level derivation path (e.g.
+	// m/84'/0'/0'); each input and change output's hint path is
+	// AccountPath/branch/index, with the branch supplied by Branch for
+	// inputs and ChangeOptions.Branch for change.
+	AccountPath string
+	// Branch is the derivation branch (conventionally 0, external) that
+	// spendable UTXOs' ChildIndex is relative to.
+	Branch uint32
+
+	accountXpub *hdkeychain.ExtendedKey
+}
+
+// NewBuilder returns a Builder bound to the wallet identified by
+// masterFingerprint, the account-level accountPath and accountXpub (one
+// level above a default Wallet's own path, e.g. the parent of
+// Wallet.ExtendedPublicKey's path), and branch, the chain spendable UTXOs
+// are derived from. Deriving from the account level, rather than a single
+// branch, lets Build also hint a change output on a different branch (see
+// ChangeOptions.Branch).
+func NewBuilder(masterFingerprint [4]byte, accountPath string, branch uint32, accountXpub string, params *chaincfg.Params) (*Builder, error) {
+	key, err := hdkeychain.NewKeyFromString(accountXpub)
+	if err != nil {
+		return nil, err
+	}
+	return &Builder{MasterFingerprint: masterFingerprint, AccountPath: accountPath, Branch: branch, accountXpub: key}, nil
+}
+
+// Build selects coins from utxos to cover recipients plus a fee at
+// feeRateSatPerVB, optionally returns change via change, and assembles the
+// resulting PSBT. The fee actually paid is returned alongside the packet.
+func (b *Builder) Build(utxos []UTXO, recipients []Recipient, feeRateSatPerVB float64, change *ChangeOptions) (*psbt.Packet, btcutil.Amount, error) {
+	if len(utxos) == 0 {
+		return nil, 0, errors.New(ErrNoUTXOs)
+	}
+	if len(recipients) == 0 {
+		return nil, 0, errors.New(ErrNoRecipients)
+	}
+	if feeRateSatPerVB <= 0 {
+		return nil, 0, errors.New(ErrInvalidFeeRate)
+	}
+
+	var target btcutil.Amount
+	for _, r := range recipients {
+		target += r.Amount
+	}
+
+	selected, fee, changeAmount, err := selectCoins(utxos, target, feeRateSatPerVB, change != nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if changeAmount > 0 && change == nil {
+		return nil, 0, errors.New(ErrMissingChangeInfo)
+	}
+
+	// Anything the selection overpays without a change output is paid as
+	// fee too, so report the difference between inputs and outputs.
+	var inputTotal btcutil.Amount
+	for _, u := range selected {
+		inputTotal += u.Amount
+	}
+	fee = inputTotal - target - changeAmount
+
+	unsignedTx := wire.NewMsgTx(wire.TxVersion)
+	for _, u := range selected {
+		unsignedTx.AddTxIn(wire.NewTxIn(&u.Outpoint, nil, nil))
+	}
+	for _, r := range recipients {
+		pkScript, err := txscriptPayToAddr(r.Address)
+		if err != nil {
+			return nil, 0, err
+		}
+		unsignedTx.AddTxOut(wire.NewTxOut(int64(r.Amount), pkScript))
+	}
+	if changeAmount > 0 {
+		pkScript, err := txscriptPayToAddr(change.Address)
+		if err != nil {
+			return nil, 0, err
+		}
+		unsignedTx.AddTxOut(wire.NewTxOut(int64(changeAmount), pkScript))
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(unsignedTx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, u := range selected {
+		path, err := b.childPath(b.Branch, u.ChildIndex)
+		if err != nil {
+			return nil, 0, err
+		}
+		pubKey, err := b.derivePubKey(b.Branch, u.ChildIndex)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		packet.Inputs[i].WitnessUtxo = &wire.TxOut{
+			Value:    int64(u.Amount),
+			PkScript: u.PkScript,
+		}
+		packet.Inputs[i].Bip32Derivation = []*psbt.Bip32Derivation{
+			{
+				PubKey:               pubKey.SerializeCompressed(),
+				MasterKeyFingerprint: fingerprintToUint32(b.MasterFingerprint),
+				Bip32Path:            path,
+			},
+		}
+	}
+
+	if changeAmount > 0 {
+		changePath, err := b.childPath(change.Branch, change.Index)
+		if err != nil {
+			return nil, 0, err
+		}
+		changePubKey, err := b.derivePubKey(change.Branch, change.Index)
+		if err != nil {
+			return nil, 0, err
+		}
+		packet.Outputs[len(packet.Outputs)-1].Bip32Derivation = []*psbt.Bip32Derivation{
+			{
+				PubKey:               changePubKey.SerializeCompressed(),
+				MasterKeyFingerprint: fingerprintToUint32(b.MasterFingerprint),
+				Bip32Path:            changePath,
+			},
+		}
+	}
+
+	return packet, fee, nil
+}
+
+// childPath expands AccountPath into its full uint32 derivation steps (with
+// the hardened bit set per BIP32) and appends branch and index, as PSBT's
+// BIP32 derivation field expects the full path from the master key.
+func (b *Builder) childPath(branch, index uint32) ([]uint32, error) {
+	dpath, err := accounts.ParseDerivationPath(b.AccountPath)
+	if err != nil {
+		return nil, err
+	}
+	path := make([]uint32, 0, len(dpath)+2)
+	for _, n := range dpath {
+		path = append(path, uint32(n))
+	}
+	return append(path, branch, index), nil
+}
+
+// derivePubKey derives the public key at AccountPath/branch/index from the
+// Builder's account-level extended public key.
+func (b *Builder) derivePubKey(branch, index uint32) (*btcec.PublicKey, error) {
+	branchKey, err := b.accountXpub.Derive(branch)
+	if err != nil {
+		return nil, err
+	}
+	childKey, err := branchKey.Derive(index)
+	if err != nil {
+		return nil, err
+	}
+	return childKey.ECPubKey()
+}
+
+// txscriptPayToAddr builds the output script paying addr.
+func txscriptPayToAddr(addr btcutil.Address) ([]byte, error) {
+	return txscript.PayToAddrScript(addr)
+}
+
+// selectCoins picks a subset of utxos covering target plus the fee implied
+// by feeRateSatPerVB. It first tries a branch-and-bound search for an exact
+// (no-change) match within a small waste tolerance, the way Bitcoin Core's
+// coin selector does, and falls back to a single accumulative pass -
+// largest amount first - if no good exact match is found.
+func selectCoins(utxos []UTXO, target btcutil.Amount, feeRateSatPerVB float64, allowChange bool) (selected []UTXO, fee, change btcutil.Amount, err error) {
+	const (
+		inputVBytes  = 68 // approximate vsize of a P2WPKH input
+		overheadVB   = 11
+		outputVBytes = 31 // approximate vsize of a P2WPKH output
+	)
+
+	baseFee := btcutil.Amount(feeRateSatPerVB * float64(overheadVB+outputVBytes))
+
+	sorted := make([]UTXO, len(utxos))
+	copy(sorted, utxos)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+
+	if picked, ok := branchAndBound(sorted, target, feeRateSatPerVB, inputVBytes, baseFee); ok {
+		fee = baseFee + btcutil.Amount(feeRateSatPerVB*inputVBytes*float64(len(picked)))
+		return picked, fee, 0, nil
+	}
+
+	var (
+		accumulated btcutil.Amount
+		picked      []UTXO
+	)
+	for _, u := range sorted {
+		picked = append(picked, u)
+		accumulated += u.Amount
+		fee = baseFee + btcutil.Amount(feeRateSatPerVB*inputVBytes*float64(len(picked)))
+		if accumulated >= target+fee {
+			if allowChange {
+				change = accumulated - target - fee
+			}
+			return picked, fee, change, nil
+		}
+	}
+	return nil, 0, 0, errors.New(ErrInsufficientFunds)
+}
+
+// maxBranchAndBoundTries bounds branchAndBound's search the way Bitcoin
+// Core's coin selector bounds its own BnB pass, so a UTXO set/target
+// combination with no exact match falls back to the accumulator instead of
+// exhausting the full 2^n search tree.
+const maxBranchAndBoundTries = 100_000
+
+// branchAndBound performs a depth-first search over sorted (descending)
+// utxos for a subset whose total falls within [target+fee, target+fee+tolerance],
+// so the payment can be made without creating a change output. It gives up,
+// letting the caller fall back to the accumulator, once maxBranchAndBoundTries
+// nodes have been explored without a match.
+func branchAndBound(sorted []UTXO, target btcutil.Amount, feeRateSatPerVB float64, inputVBytes int, baseFee btcutil.Amount) ([]UTXO, bool) {
+	const tolerance = 1000 // sats of acceptable overpayment, avoids dust change
+
+	var (
+		best     []UTXO
+		bestSeen = false
+		tries    int
+	)
+
+	var search func(idx int, selected []UTXO, sum btcutil.Amount)
+	search = func(idx int, selected []UTXO, sum btcutil.Amount) {
+		if bestSeen || tries >= maxBranchAndBoundTries {
+			return
+		}
+		tries++
+
+		fee := baseFee + btcutil.Amount(feeRateSatPerVB*float64(inputVBytes)*float64(len(selected)))
+		if sum >= target+fee {
+			if sum-target-fee <= tolerance {
+				best = append([]UTXO(nil), selected...)
+				bestSeen = true
+			}
+			return
+		}
+		if idx >= len(sorted) {
+			return
+		}
+		search(idx+1, append(selected, sorted[idx]), sum+sorted[idx].Amount)
+		search(idx+1, selected, sum)
+	}
+	search(0, nil, 0)
+	return best, bestSeen
+}
+
+func fingerprintToUint32(fp [4]byte) uint32 {
+	return uint32(fp[0]) | uint32(fp[1])<<8 | uint32(fp[2])<<16 | uint32(fp[3])<<24
+}
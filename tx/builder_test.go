@@ -0,0 +1,165 @@
+package tx
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+
+	segwit "github.com/ariden83/segwit.go"
+)
+
+// accountPathOf strips the last derivation step from path, the way
+// Builder.AccountPath relates to a default Wallet's own branch-depth path.
+func accountPathOf(path string) string {
+	idx := strings.LastIndex(path, "/")
+	return path[:idx]
+}
+
+func testUTXO(amount btcutil.Amount, idx uint32) UTXO {
+	return UTXO{
+		Outpoint:   wire.OutPoint{Index: idx},
+		Amount:     amount,
+		PkScript:   []byte{0x00, 0x14},
+		ChildIndex: idx,
+	}
+}
+
+func Test_SelectCoins_ExactMatch(t *testing.T) {
+	utxos := []UTXO{testUTXO(50_000, 0), testUTXO(30_000, 1), testUTXO(20_000, 2)}
+
+	selected, fee, change, err := selectCoins(utxos, 49_800, 1, true)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, selected)
+	assert.Zero(t, change, "an exact branch-and-bound match should not need change")
+	assert.Greater(t, int64(fee), int64(0))
+}
+
+func Test_SelectCoins_FallsBackWithChange(t *testing.T) {
+	utxos := []UTXO{testUTXO(100_000, 0)}
+
+	selected, fee, change, err := selectCoins(utxos, 1_000, 1, true)
+	assert.NoError(t, err)
+	assert.Len(t, selected, 1)
+	assert.Greater(t, int64(change), int64(0))
+	assert.Greater(t, int64(fee), int64(0))
+}
+
+func Test_SelectCoins_InsufficientFunds(t *testing.T) {
+	utxos := []UTXO{testUTXO(1_000, 0)}
+
+	_, _, _, err := selectCoins(utxos, 1_000_000, 1, true)
+	assert.EqualError(t, err, ErrInsufficientFunds)
+}
+
+// Test_SelectCoins_BranchAndBound_FallsBackOnBudget guards against the BnB
+// search hanging: with enough UTXOs to make an exhaustive search
+// intractable and a target it can't hit within tolerance, selectCoins must
+// still return promptly by falling back to the accumulator once
+// maxBranchAndBoundTries is exhausted.
+func Test_SelectCoins_BranchAndBound_FallsBackOnBudget(t *testing.T) {
+	utxos := make([]UTXO, 30)
+	for i := range utxos {
+		utxos[i] = testUTXO(btcutil.Amount(10_000+i), uint32(i))
+	}
+
+	done := make(chan struct{})
+	var (
+		selected []UTXO
+		err      error
+	)
+	go func() {
+		selected, _, _, err = selectCoins(utxos, 200_000, 1, true)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		assert.NoError(t, err)
+		assert.NotEmpty(t, selected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("selectCoins did not return within the budget; branchAndBound is unbounded")
+	}
+}
+
+// testBuilder constructs a Builder bound to a deterministic wallet's account
+// (one level above its own default branch), so Build and Sign tests can
+// exercise a full PSBT round trip, including a change output on a different
+// branch than the one the spent UTXO came from.
+func testBuilder(t *testing.T) (*Builder, *segwit.Wallet) {
+	t.Helper()
+
+	const zeroMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	wallet, err := segwit.New(&segwit.Config{Mnemonic: zeroMnemonic, Network: segwit.NetworkMainnet})
+	assert.NoError(t, err)
+
+	fp, err := wallet.Fingerprint()
+	assert.NoError(t, err)
+
+	accountPath := accountPathOf(wallet.Path())
+	accountWallet, err := segwit.New(&segwit.Config{Mnemonic: zeroMnemonic, Path: accountPath, Network: segwit.NetworkMainnet})
+	assert.NoError(t, err)
+	accountXpub, err := accountWallet.ExtendedPublicKey()
+	assert.NoError(t, err)
+
+	builder, err := NewBuilder(fp, accountPath, 0, accountXpub, &chaincfg.MainNetParams)
+	assert.NoError(t, err)
+	return builder, wallet
+}
+
+func Test_Builder_Build_And_Sign(t *testing.T) {
+	builder, wallet := testBuilder(t)
+
+	source, err := wallet.Derive(uint32(0))
+	assert.NoError(t, err)
+	destWallet, err := segwit.New(&segwit.Config{Mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow", Network: segwit.NetworkMainnet})
+	assert.NoError(t, err)
+
+	const zeroMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	internalPath := accountPathOf(wallet.Path()) + "/1"
+	internalWallet, err := segwit.New(&segwit.Config{Mnemonic: zeroMnemonic, Path: internalPath, Network: segwit.NetworkMainnet})
+	assert.NoError(t, err)
+	changeAddr, err := internalWallet.Derive(uint32(0))
+	assert.NoError(t, err)
+
+	pkScript, err := txscriptPayToAddr(source.Address())
+	assert.NoError(t, err)
+
+	utxos := []UTXO{{
+		Outpoint:   wire.OutPoint{Index: 0},
+		Amount:     100_000,
+		PkScript:   pkScript,
+		ChildIndex: 0,
+	}}
+	recipients := []Recipient{{Address: destWallet.Address(), Amount: 50_000}}
+	change := &ChangeOptions{Address: changeAddr.Address(), Branch: 1, Index: 0}
+
+	packet, fee, err := builder.Build(utxos, recipients, 1, change)
+	assert.NoError(t, err)
+	assert.Greater(t, int64(fee), int64(0))
+	assert.Len(t, packet.Inputs, 1)
+
+	// The change output's BIP32 derivation hint must point at its own
+	// internal-branch key, not be left empty the way an unrecognized
+	// external destination would be.
+	assert.Len(t, packet.Outputs, 2)
+	changeOutput := packet.Outputs[len(packet.Outputs)-1]
+	assert.Len(t, changeOutput.Bip32Derivation, 1)
+	changeWIF, err := changeAddr.PrivateKey()
+	assert.NoError(t, err)
+	decoded, err := btcutil.DecodeWIF(changeWIF)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.PrivKey.PubKey().SerializeCompressed(), changeOutput.Bip32Derivation[0].PubKey)
+
+	assert.NoError(t, Sign(wallet, packet))
+	assert.Len(t, packet.Inputs[0].PartialSigs, 1)
+
+	signedTx, err := Finalize(packet)
+	assert.NoError(t, err)
+	assert.Len(t, signedTx.TxIn, 1)
+	assert.NotEmpty(t, signedTx.TxIn[0].Witness)
+}
@@ -0,0 +1,107 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	segwit "github.com/ariden83/segwit.go"
+)
+
+const (
+	ErrNotWitnessUtxo = "psbt input is missing its witness UTXO"
+	ErrFinalizeInput  = "failed to finalize psbt input"
+)
+
+// Sign walks every input of p, re-derives its signing key from w through the
+// child index recorded in its BIP32 derivation hint, computes the BIP143
+// witness v0 sighash, and fills in the input's PartialSig.
+func Sign(w *segwit.Wallet, p *psbt.Packet) error {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(p.Inputs))
+	for i, in := range p.UnsignedTx.TxIn {
+		if p.Inputs[i].WitnessUtxo == nil {
+			return errors.New(ErrNotWitnessUtxo)
+		}
+		prevOuts[in.PreviousOutPoint] = p.Inputs[i].WitnessUtxo
+	}
+	fetcher := txscript.NewMultiPrevOutFetcher(prevOuts)
+	hashCache := txscript.NewTxSigHashes(p.UnsignedTx, fetcher)
+
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if len(in.Bip32Derivation) == 0 {
+			continue
+		}
+		childIndex := in.Bip32Derivation[0].Bip32Path[len(in.Bip32Derivation[0].Bip32Path)-1]
+
+		child, err := w.Derive(childIndex)
+		if err != nil {
+			return err
+		}
+		wif, err := child.PrivateKey()
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		decoded, err := btcutil.DecodeWIF(wif)
+		if err != nil {
+			return err
+		}
+
+		scriptCode, err := p2pkhScriptFromWitnessProgram(in.WitnessUtxo.PkScript)
+		if err != nil {
+			return err
+		}
+
+		sigHash, err := txscript.CalcWitnessSigHash(
+			scriptCode, hashCache, txscript.SigHashAll, p.UnsignedTx, i, in.WitnessUtxo.Value)
+		if err != nil {
+			return err
+		}
+
+		signature := ecdsa.Sign(decoded.PrivKey, sigHash)
+		sig := append(signature.Serialize(), byte(txscript.SigHashAll))
+
+		in.PartialSigs = append(in.PartialSigs, &psbt.PartialSig{
+			PubKey:    decoded.PrivKey.PubKey().SerializeCompressed(),
+			Signature: sig,
+		})
+	}
+	return nil
+}
+
+// Finalize builds each input's final witness stack from its partial
+// signature and extracts the ready-to-broadcast raw transaction.
+func Finalize(p *psbt.Packet) (*wire.MsgTx, error) {
+	for i, in := range p.Inputs {
+		if len(in.PartialSigs) != 1 {
+			return nil, fmt.Errorf("%s: input %d has %d signatures, want 1", ErrFinalizeInput, i, len(in.PartialSigs))
+		}
+	}
+
+	if err := psbt.MaybeFinalizeAll(p); err != nil {
+		return nil, fmt.Errorf("%s: %w", ErrFinalizeInput, err)
+	}
+	return psbt.Extract(p)
+}
+
+// p2pkhScriptFromWitnessProgram rebuilds the classic P2PKH script BIP143
+// uses as the "script code" when signing a P2WPKH input, from its witness
+// program (OP_0 <pubKeyHash>).
+func p2pkhScriptFromWitnessProgram(witnessProgram []byte) ([]byte, error) {
+	if len(witnessProgram) != 22 {
+		return nil, errors.New(ErrNotWitnessUtxo)
+	}
+	pubKeyHash := witnessProgram[2:]
+	return txscript.NewScriptBuilder().
+		AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).
+		AddData(pubKeyHash).
+		AddOp(txscript.OP_EQUALVERIFY).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+}